@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// preset is the board settings of a daily challenge.
+type preset struct {
+	boardSize  int
+	blackHoles int
+}
+
+var presets = map[string]preset{
+	"beginner":     {boardSize: 8, blackHoles: 10},
+	"intermediate": {boardSize: 16, blackHoles: 40},
+	"expert":       {boardSize: 24, blackHoles: 99},
+}
+
+// dailyAttempt is the result of the daily challenge of the date and preset.
+type dailyAttempt struct {
+	Date    string  `json:"date"`
+	Preset  string  `json:"preset"`
+	Status  string  `json:"status"`
+	Seconds float64 `json:"seconds,omitempty"`
+}
+
+// dailyStats is attempts of daily challenges stored locally.
+type dailyStats struct {
+	Attempts []dailyAttempt `json:"attempts"`
+}
+
+var errAttempted = errors.New("the daily challenge is already attempted today")
+
+// daily plays the board of the day once per day and preset.
+func daily(args []string) {
+	flags := flag.NewFlagSet("daily", flag.ExitOnError)
+	presetName := flags.String("preset", "beginner", "board preset: "+strings.Join(presetNames(), ", "))
+	statsFile := flags.String("stats", defaultStatsFile(), "file of daily challenge stats")
+	render := flags.String("render", "ascii", "board rendering: "+strings.Join(rendererNames(), ", "))
+	_ = flags.Parse(args)
+
+	r, err := newRenderer(*render)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+
+	p, ok := presets[*presetName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown preset %q\n", *presetName)
+		os.Exit(2)
+	}
+
+	now := time.Now().UTC()
+	date := now.Format("2006-01-02")
+
+	stats, err := loadDailyStats(*statsFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	// the attempt is recorded before play, so quitting counts as well
+	attempt := dailyAttempt{Date: date, Preset: *presetName, Status: "in_progress"}
+	if err := stats.add(attempt); err != nil {
+		previous, _ := stats.find(date, *presetName)
+		fmt.Fprintf(os.Stderr, "%s: %s\n", err.Error(), describeAttempt(previous))
+		os.Exit(1)
+	}
+	if err := saveDailyStats(*statsFile, stats); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("Daily challenge %s (%s)\n", date, *presetName)
+	g := game.NewGame(p.boardSize, p.blackHoles, game.WithSeed(game.DailySeed(now, *presetName)))
+	ga := newGameAdapter(g, os.Stdin, os.Stdout)
+	ga.renderer = r
+	ga.Play()
+
+	attempt.Status = gameStatus(g)
+	attempt.Seconds = g.Elapsed().Round(time.Millisecond).Seconds()
+	stats.update(attempt)
+	if err := saveDailyStats(*statsFile, stats); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	fmt.Println(describeAttempt(attempt))
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func defaultStatsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+
+	return filepath.Join(dir, "proxx", "daily.json")
+}
+
+func describeAttempt(a dailyAttempt) string {
+	switch a.Status {
+	case "won":
+		return fmt.Sprintf("%s %s won in %.1fs", a.Date, a.Preset, a.Seconds)
+	case "lost":
+		return fmt.Sprintf("%s %s lost after %.1fs", a.Date, a.Preset, a.Seconds)
+	case "resigned":
+		return fmt.Sprintf("%s %s resigned after %.1fs", a.Date, a.Preset, a.Seconds)
+	default:
+		return fmt.Sprintf("%s %s left unfinished", a.Date, a.Preset)
+	}
+}
+
+// loadDailyStats reads stats from the file. Missing file has no attempts.
+func loadDailyStats(name string) (*dailyStats, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return &dailyStats{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dailyStats{}
+	if err := json.Unmarshal(data, stats); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return stats, nil
+}
+
+func saveDailyStats(name string, stats *dailyStats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, append(data, '\n'), 0o644)
+}
+
+func (s *dailyStats) find(date, preset string) (dailyAttempt, bool) {
+	for _, a := range s.Attempts {
+		if a.Date == date && a.Preset == preset {
+			return a, true
+		}
+	}
+
+	return dailyAttempt{}, false
+}
+
+// add records the attempt. If the date and preset is already attempted, then
+// errAttempted is returned.
+func (s *dailyStats) add(a dailyAttempt) error {
+	if _, ok := s.find(a.Date, a.Preset); ok {
+		return errAttempted
+	}
+
+	s.Attempts = append(s.Attempts, a)
+
+	return nil
+}
+
+// update replaces the recorded attempt of the same date and preset.
+func (s *dailyStats) update(a dailyAttempt) {
+	for i := range s.Attempts {
+		if s.Attempts[i].Date == a.Date && s.Attempts[i].Preset == a.Preset {
+			s.Attempts[i] = a
+		}
+	}
+}
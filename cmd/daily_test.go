@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyStats(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "proxx", "daily.json")
+
+	stats, err := loadDailyStats(name)
+	require.NoError(t, err)
+	assert.Empty(t, stats.Attempts)
+
+	attempt := dailyAttempt{Date: "2026-10-18", Preset: "beginner", Status: "in_progress"}
+	require.NoError(t, stats.add(attempt))
+	assert.ErrorIs(t, stats.add(attempt), errAttempted)
+	require.NoError(t, stats.add(dailyAttempt{Date: "2026-10-18", Preset: "expert", Status: "in_progress"}))
+	require.NoError(t, stats.add(dailyAttempt{Date: "2026-10-19", Preset: "beginner", Status: "in_progress"}))
+
+	attempt.Status = "won"
+	attempt.Seconds = 12.5
+	stats.update(attempt)
+	require.NoError(t, saveDailyStats(name, stats))
+
+	loaded, err := loadDailyStats(name)
+	require.NoError(t, err)
+	assert.Equal(t, stats, loaded)
+
+	got, ok := loaded.find("2026-10-18", "beginner")
+	assert.True(t, ok)
+	assert.Equal(t, "2026-10-18 beginner won in 12.5s", describeAttempt(got))
+	assert.ErrorIs(t, loaded.add(attempt), errAttempted)
+}
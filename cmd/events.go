@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// subscriberBuffer is the number of events a subscriber may lag behind before
+// it is disconnected.
+const subscriberBuffer = 64
+
+type cellChange struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+	cellView
+}
+
+type statusView struct {
+	Status string `json:"status"`
+}
+
+type gameOverView struct {
+	Status   string    `json:"status"`
+	FailedAt *position `json:"failedAt,omitempty"`
+}
+
+// streamEvent is a server-sent event.
+type streamEvent struct {
+	name string
+	data interface{}
+}
+
+// eventBroker fans out events of games to their subscribers.
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan streamEvent]struct{}
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subscribers: make(map[string]map[chan streamEvent]struct{})}
+}
+
+// subscribe returns a channel receiving events of the game. The channel is
+// closed by unsubscribe or if the subscriber falls behind.
+func (b *eventBroker) subscribe(id string) chan streamEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan streamEvent, subscriberBuffer)
+	if b.subscribers[id] == nil {
+		b.subscribers[id] = make(map[chan streamEvent]struct{})
+	}
+	b.subscribers[id][ch] = struct{}{}
+
+	return ch
+}
+
+func (b *eventBroker) unsubscribe(id string, ch chan streamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(id, ch)
+}
+
+func (b *eventBroker) publish(id string, events ...streamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[id] {
+	sending:
+		for _, e := range events {
+			select {
+			case ch <- e:
+			default:
+				b.remove(id, ch)
+
+				break sending
+			}
+		}
+	}
+}
+
+// closeGame closes channels of all subscribers of the game, so their streams
+// end.
+func (b *eventBroker) closeGame(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[id] {
+		b.remove(id, ch)
+	}
+}
+
+func (b *eventBroker) remove(id string, ch chan streamEvent) {
+	if _, ok := b.subscribers[id][ch]; !ok {
+		return
+	}
+
+	delete(b.subscribers[id], ch)
+	if len(b.subscribers[id]) == 0 {
+		delete(b.subscribers, id)
+	}
+	close(ch)
+}
+
+// newStreamEvent presents the game event for clients.
+func newStreamEvent(e game.Event) (streamEvent, bool) {
+	switch e := e.(type) {
+	case game.CellRevealed:
+		changes := make([]cellChange, len(e.Cells))
+		for i, c := range e.Cells {
+			changes[i] = cellChange{
+				Row:      c.Row,
+				Column:   c.Column,
+				cellView: newCellView(game.VisibleState, c.Content),
+			}
+		}
+
+		return streamEvent{name: "cells", data: changes}, true
+	case game.CellFlagged:
+		state := game.HiddenState
+		if e.Flagged {
+			state = game.FlaggedState
+		}
+		changes := []cellChange{{
+			Row:      e.Row,
+			Column:   e.Column,
+			cellView: newCellView(state, game.UnknownCellValue),
+		}}
+
+		return streamEvent{name: "cells", data: changes}, true
+	case game.GameWon:
+		return streamEvent{name: "gameover", data: gameOverView{Status: "won"}}, true
+	case game.GameLost:
+		view := gameOverView{Status: "lost", FailedAt: &position{Row: e.Row, Column: e.Column}}
+
+		return streamEvent{name: "gameover", data: view}, true
+	case game.GameResigned:
+		return streamEvent{name: "gameover", data: gameOverView{Status: "resigned"}}, true
+	case game.GamePaused:
+		return streamEvent{name: "status", data: statusView{Status: "paused"}}, true
+	case game.GameResumed:
+		return streamEvent{name: "status", data: statusView{Status: "in_progress"}}, true
+	default:
+		return streamEvent{}, false
+	}
+}
+
+func gameOverEvent(g *game.Game) streamEvent {
+	view := gameOverView{Status: gameStatus(g)}
+	if row, column, failed := g.FailedAt(); failed {
+		view.FailedAt = &position{Row: row, Column: column}
+	}
+
+	return streamEvent{name: "gameover", data: view}
+}
+
+// streamEvents streams events of the game to the client as server-sent
+// events. The stream starts with the "state" event carrying the whole masked
+// game state and is followed by "cells" events with changed cells. The stream
+// ends after the "gameover" event or once the game is evicted.
+func (s *server) streamEvents(w http.ResponseWriter, r *http.Request, id string, sg *game.SyncGame) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+
+		return
+	}
+
+	ch := s.events.subscribe(id)
+	defer s.events.unsubscribe(id, ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	// the game is read after subscribing, so no changes are missed
+	var state gameView
+	var gameOver *streamEvent
+	sg.Read(func(g *game.Game) {
+		state = newGameView(id, g)
+		if g.Completed() {
+			e := gameOverEvent(g)
+			gameOver = &e
+		}
+	})
+
+	if err := writeEvent(w, streamEvent{name: "state", data: state}); err != nil {
+		return
+	}
+	if gameOver != nil {
+		_ = writeEvent(w, *gameOver)
+		flusher.Flush()
+
+		return
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+
+			if e.name == "gameover" {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e streamEvent) error {
+	data, err := json.Marshal(e.data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, data)
+
+	return err
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads the next server-sent event from the stream.
+func readEvent(t *testing.T, r *bufio.Reader) (name string, data string) {
+	t.Helper()
+
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return name, data
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestServer_streamEvents(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 3, BlackHoles: 0})
+	gameURL := ts.URL + "/games/" + created.ID
+
+	resp, err := http.Get(gameURL + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	stream := bufio.NewReader(resp.Body)
+
+	name, data := readEvent(t, stream)
+	assert.Equal(t, "state", name)
+	var state gameView
+	require.NoError(t, json.Unmarshal([]byte(data), &state))
+	assert.Equal(t, created, state)
+
+	status := doJSON(t, http.MethodPost, gameURL+"/flag", moveRequest{Row: 0, Column: 0}, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "cells", name)
+	assert.JSONEq(t, `[{"row":0,"column":0,"state":"flagged"}]`, data)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 2, Column: 2}, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "cells", name)
+	var changes []cellChange
+	require.NoError(t, json.Unmarshal([]byte(data), &changes))
+	assert.Len(t, changes, 8)
+	for _, change := range changes {
+		assert.Equal(t, "visible", change.State)
+	}
+
+	status = doJSON(t, http.MethodPost, gameURL+"/flag", moveRequest{Row: 0, Column: 0}, nil)
+	require.Equal(t, http.StatusOK, status)
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 0, Column: 0}, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "cells", name)
+	assert.JSONEq(t, `[{"row":0,"column":0,"state":"hidden"}]`, data)
+
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "cells", name)
+	assert.JSONEq(t, `[{"row":0,"column":0,"state":"visible","value":0}]`, data)
+
+	name, data = readEvent(t, stream)
+	assert.Equal(t, "gameover", name)
+	assert.JSONEq(t, `{"status":"won"}`, data)
+
+	_, err = stream.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestServer_streamEvents_evicted(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	srv := newServer()
+	srv.store.now = clock.Now
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 3, BlackHoles: 1})
+
+	resp, err := http.Get(ts.URL + "/games/" + created.ID + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	stream := bufio.NewReader(resp.Body)
+	name, _ := readEvent(t, stream)
+	assert.Equal(t, "state", name)
+
+	// creating the next game evicts the idle one
+	clock.advance(gameTTL)
+	createTestGame(t, ts.URL, gameConfig{BoardSize: 3, BlackHoles: 1})
+
+	_, err = stream.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestEventBroker_slowSubscriber(t *testing.T) {
+	broker := newEventBroker()
+	slow := broker.subscribe("game")
+
+	for i := 0; i < subscriberBuffer; i++ {
+		broker.publish("game", streamEvent{name: "cells"})
+	}
+	broker.publish("game", streamEvent{name: "cells"}, streamEvent{name: "gameover"})
+
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+
+	// unsubscribing the dropped subscriber is safe
+	broker.unsubscribe("game", slow)
+}
@@ -2,48 +2,197 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kalynv/proxx/game"
 )
 
 func main() {
-	boardSize := 3
-	blackHoles := 2
-	theGame := game.NewGame(boardSize, blackHoles)
-	adapter := newGameAdapter(theGame, os.Stdin, os.Stdout)
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		serve(os.Args[2:])
 
-	adapter.Play()
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "daily" {
+		daily(os.Args[2:])
+
+		return
+	}
+
+	wrap := flag.Bool("wrap", false, "wrap board edges around, so every cell has eight neighbours")
+	hex := flag.Bool("hex", false, "play on hexagonal cells, so every cell has six neighbours")
+	mask := flag.String("mask", "", `neighbourhood mask: "knight", "radius:N" or "row:column" offsets separated by commas`)
+	layers := flag.Int("layers", 0, "play on a three-dimensional board of the number of layers, so every cell has 26 neighbours")
+	shapeFile := flag.String("shape", "", "play on the board shaped by the ASCII template file, where spaces are absent cells")
+	boardFile := flag.String("board", "", "play the board of the text file, where '*' are black holes and '.' are safe cells")
+	share := flag.Bool("share", false, "print the code of the board to share it")
+	code := flag.String("code", "", "play the board of the shared code")
+	render := flag.String("render", "ascii", "board rendering: "+strings.Join(rendererNames(), ", "))
+	seed := flag.Uint64("seed", 0, "make the board of the seed, so the same seed and settings always make the same board")
+	flag.Parse()
+
+	if *code != "" && (topologies(*wrap, *hex, *mask, *layers) > 0 || *shapeFile != "" || *boardFile != "") {
+		fmt.Fprintln(os.Stderr, "-code holds the whole board and can not be combined with other board flags")
+		os.Exit(2)
+	}
+
+	if *shapeFile != "" && *layers != 0 {
+		fmt.Fprintln(os.Stderr, "-shape and -layers can not be combined")
+		os.Exit(2)
+	}
+	if *boardFile != "" && (*shapeFile != "" || *layers != 0) {
+		fmt.Fprintln(os.Stderr, "-board can not be combined with -shape or -layers")
+		os.Exit(2)
+	}
+
+	if topologies(*wrap, *hex, *mask, *layers) > 1 {
+		fmt.Fprintln(os.Stderr, "-wrap, -hex, -mask and -layers can not be combined")
+		os.Exit(2)
+	}
+	if *layers < 0 || *layers > maxLayers {
+		fmt.Fprintf(os.Stderr, "-layers must be in [1, %d]\n", maxLayers)
+		os.Exit(2)
+	}
+
+	s := &settings{boardSize: 3, blackHoles: 2, layers: *layers, boardFile: *boardFile, code: *code, share: *share}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			s.seed = seed
+		}
+	})
+	if *wrap {
+		s.options = append(s.options, game.WithTopology(game.Torus{}))
+	}
+	if *hex {
+		s.options = append(s.options, game.WithTopology(game.Hex{}))
+	}
+	if *mask != "" {
+		m, err := parseMask(*mask)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(2)
+		}
+		s.options = append(s.options, game.WithTopology(m))
+	}
+	r, err := newRenderer(*render)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	s.renderer = r
+	if *shapeFile != "" {
+		shape, err := readShape(*shapeFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(2)
+		}
+		s.shape = &shape
+	}
+
+	if err := playSession(s, os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+}
+
+// topologies returns the number of topology flags set.
+func topologies(wrap, hex bool, mask string, layers int) int {
+	n := 0
+	for _, set := range []bool{wrap, hex, mask != "", layers != 0} {
+		if set {
+			n++
+		}
+	}
+
+	return n
+}
+
+func readShape(name string) (game.Shape, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return game.Shape{}, err
+	}
+	defer f.Close()
+
+	shape, err := game.ParseShape(f)
+	if err != nil {
+		return game.Shape{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return shape, nil
+}
+
+func readBoard(name string, options []game.Option) (*game.Game, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := game.ParseBoard(f, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return g, nil
 }
 
 func newGameAdapter(g *game.Game, in io.Reader, out io.Writer) *gameAdapter {
 	return &gameAdapter{
-		game: g,
-		in:   in,
-		out:  out,
+		game:     g,
+		in:       bufio.NewReader(in),
+		out:      out,
+		renderer: asciiRenderer{},
 	}
 }
 
+// maxLayers limits layers of three-dimensional boards played in console.
+const maxLayers = 9
+
 type gameAdapter struct {
-	game *game.Game
-	in   io.Reader
-	out  io.Writer
+	game     *game.Game
+	in       *bufio.Reader
+	out      io.Writer
+	renderer renderer
+	view     game.Board
+	layer    int
 }
 
 func (ga *gameAdapter) Play() {
 	fmt.Fprintln(ga.out, "Game started!")
 
+	ga.view = ga.game.PlayerBoard()
+
 	for !ga.game.Completed() {
-		ga.displayBoard(presentCellAtGameTime)
-		row, column := ga.readRevealCell()
-		ga.game.RevealCell(row, column)
+		changes, version := ga.game.ChangesSince(ga.view.Version)
+		ga.view.Apply(changes, version)
+
+		ga.displayBoard(presentPlayerBoard(ga.view))
+		fmt.Fprintf(ga.out, "Time: %s\n", ga.game.Elapsed().Round(time.Second))
+		a, ok := ga.readAction()
+		if !ok {
+			continue
+		}
+		row, column, ok := ga.readCell()
+		if !ok {
+			continue
+		}
+		if err := applyMove(ga.game, a, row, column); err != nil {
+			fmt.Fprintln(ga.out, err.Error())
+		}
 	}
 
-	ga.displayBoard(presentCellRevealed)
+	postMortem := presentPostMortem(ga.game)
+	for ga.layer = 0; ga.layer < ga.layers(); ga.layer++ {
+		ga.displayBoard(postMortem)
+	}
 
 	if ga.game.Won() {
 		fmt.Fprintln(ga.out, "You won!")
@@ -51,15 +200,110 @@ func (ga *gameAdapter) Play() {
 	if ga.game.Lost() {
 		fmt.Fprintln(ga.out, "You lost.")
 	}
+	if ga.game.Status() == game.Resigned {
+		fmt.Fprintln(ga.out, "You resigned.")
+	}
 	fmt.Fprintln(ga.out, "Game over")
 }
 
-func (ga *gameAdapter) readRevealCell() (row, column int) {
-	fmt.Fprint(ga.out, "\nRevealing cell by row and column\n")
+// readAction reads the kind of the move the player wants to make. Players of
+// three-dimensional boards may switch the displayed layer meanwhile. Players
+// may pause or resign the game as well, then false is returned as no move is
+// to be made.
+func (ga *gameAdapter) readAction() (action, bool) {
+	layers := ga.layers()
+	for {
+		if layers > 1 {
+			fmt.Fprint(ga.out, "Enter action (r - reveal, f - toggle flag, c - chord, p - pause, q - resign, < > - switch layer): ")
+		} else {
+			fmt.Fprint(ga.out, "Enter action (r - reveal, f - toggle flag, c - chord, p - pause, q - resign): ")
+		}
+		line, err := readLine(ga.in)
+		if errors.Is(err, io.EOF) {
+			ga.abandon()
+
+			return 0, false
+		}
+		if err != nil {
+			fmt.Fprintf(ga.out, "%s\n", err.Error())
+
+			continue
+		}
+
+		switch line {
+		case "r", "":
+			return revealAction, true
+		case "f":
+			return flagAction, true
+		case "c":
+			return chordAction, true
+		case "p":
+			if err := changeStatus(ga.game, game.Paused); err != nil {
+				fmt.Fprintln(ga.out, err.Error())
+
+				continue
+			}
+			ga.pause()
+
+			return 0, false
+		case "q":
+			if err := changeStatus(ga.game, game.Resigned); err != nil {
+				fmt.Fprintln(ga.out, err.Error())
+
+				continue
+			}
+
+			return 0, false
+		case "<", ">":
+			if layers > 1 {
+				if line == "<" {
+					ga.layer = (ga.layer + layers - 1) % layers
+				} else {
+					ga.layer = (ga.layer + 1) % layers
+				}
+				ga.displayBoard(presentPlayerBoard(ga.view))
+
+				continue
+			}
+
+			fmt.Fprintf(ga.out, "unknown action %q\n", line)
+		default:
+			fmt.Fprintf(ga.out, "unknown action %q\n", line)
+		}
+	}
+}
+
+// pause hides the board of the paused game until the player resumes it.
+func (ga *gameAdapter) pause() {
+	fmt.Fprintln(ga.out, "Game paused, press Enter to resume")
+	if _, err := readLine(ga.in); errors.Is(err, io.EOF) {
+		ga.abandon()
+
+		return
+	}
+	_ = changeStatus(ga.game, game.InProgress)
+}
+
+// abandon resigns the game once the input is closed, as no more moves can be
+// read.
+func (ga *gameAdapter) abandon() {
+	fmt.Fprintln(ga.out)
+	_ = changeStatus(ga.game, game.Resigned)
+}
+
+// readCell reads the row and column of the cell. If the input is closed, then
+// the game is abandoned and false is returned.
+func (ga *gameAdapter) readCell() (row, column int, ok bool) {
+	fmt.Fprint(ga.out, "\nSelecting cell by row and column\n")
 	for {
 		var err error
 		fmt.Fprint(ga.out, "Enter row: ")
 		row, err = readInt(ga.in)
+		if errors.Is(err, io.EOF) {
+			ga.abandon()
+
+			return 0, 0, false
+		}
 		if err != nil {
 			fmt.Fprintf(ga.out, "%s\n", err.Error())
 
@@ -73,6 +317,11 @@ func (ga *gameAdapter) readRevealCell() (row, column int) {
 		var err error
 		fmt.Fprint(ga.out, "Enter column: ")
 		column, err = readInt(ga.in)
+		if errors.Is(err, io.EOF) {
+			ga.abandon()
+
+			return 0, 0, false
+		}
 		if err != nil {
 			fmt.Fprintf(ga.out, "%s\n", err.Error())
 
@@ -82,16 +331,52 @@ func (ga *gameAdapter) readRevealCell() (row, column int) {
 		break
 	}
 
-	return row, column
+	row, column = ga.boardAddress(row, column)
+
+	return row, column, true
+}
+
+// layers returns the number of layers of the board. Boards of more than three
+// dimensions have their outer dimensions flattened into layers.
+func (ga *gameAdapter) layers() int {
+	grid, ok := ga.game.Topology().(game.Grid)
+	if !ok {
+		return 1
+	}
+
+	layers := 1
+	dims := grid.Dims()
+	for i := 0; i < len(dims)-2; i++ {
+		layers *= dims[i]
+	}
+
+	return layers
+}
+
+// boardAddress returns the row and column of the board addressed by the row
+// and column of the displayed layer. Rows out of the layer are returned
+// negative, so they address no cell.
+func (ga *gameAdapter) boardAddress(row, column int) (int, int) {
+	layers := ga.layers()
+	if layers == 1 {
+		return row, column
+	}
+
+	rows := ga.view.Rows / layers
+	if row < 0 || row >= rows {
+		return -1, column
+	}
+
+	return ga.layer*rows + row, column
 }
 
-func readInt(r io.Reader) (int, error) {
-	line, err := bufio.NewReader(r).ReadString('\n')
+func readInt(r *bufio.Reader) (int, error) {
+	line, err := readLine(r)
 	if err != nil {
 		return 0, err
 	}
 
-	i, err := strconv.Atoi(strings.Trim(line, " \n"))
+	i, err := strconv.Atoi(line)
 	if err != nil {
 		return 0, err
 	}
@@ -99,65 +384,127 @@ func readInt(r io.Reader) (int, error) {
 	return i, nil
 }
 
-func (ga *gameAdapter) displayBoard(presentCell func(c game.Cell) rune) {
-	board := ga.game.GetState()
+// readLine reads the line without surrounding spaces. The last line of the
+// input may miss the line break. io.EOF is returned once the input is over.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if errors.Is(err, io.EOF) && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Trim(line, " \r\n"), nil
+}
+
+// displayBoard renders the presented board. Three-dimensional boards are
+// displayed by the current layer. Cells are as wide as the largest number the
+// topology may show.
+func (ga *gameAdapter) displayBoard(board [][]presentedCell) {
+	_, wrap := ga.game.Topology().(game.Torus)
+	_, hex := ga.game.Topology().(game.Hex)
 
-	presentedBoard := strings.Builder{}
-	presentedBoard.WriteString("\nBoard:\n")
+	title := "Board"
+	if wrap {
+		title = "Board (edges wrap around)"
+	}
+	if layers := ga.layers(); layers > 1 {
+		rows := len(board) / layers
+		board = board[ga.layer*rows : (ga.layer+1)*rows]
+		title = fmt.Sprintf("Board, layer %d of %d", ga.layer+1, layers)
+	}
 
+	width := len(strconv.Itoa(ga.game.Topology().MaxNeighbours()))
 	for _, row := range board {
-		presentedRow := strings.Builder{}
 		for _, cell := range row {
-			presentedRow.WriteRune(' ')
-			presentedRow.WriteRune(presentCell(cell))
+			if len(cell.text()) > width {
+				width = len(cell.text())
+			}
+		}
+	}
+
+	ga.renderer.render(ga.out, boardView{title: title, cells: board, wrap: wrap, hex: hex, width: width})
+}
+
+// presentPlayerBoard presents the board during play. Only the player view is
+// used, so contents of hidden cells can not be leaked.
+func presentPlayerBoard(view game.Board) [][]presentedCell {
+	board := make([][]presentedCell, view.Rows)
+	for i := range board {
+		row := make([]presentedCell, view.Columns)
+		for j := range row {
+			row[j] = presentCellAtGameTime(view.At(i, j))
 		}
-		presentedBoard.WriteString(presentedRow.String())
-		presentedBoard.WriteString("\n")
+		board[i] = row
 	}
 
-	presentedBoard.WriteString("\n")
+	return board
+}
+
+// presentPostMortem presents the board of the completed game.
+// It distinguishes the detonated black hole, correctly flagged black holes,
+// wrongly flagged cells and black holes left unflagged.
+func presentPostMortem(g *game.Game) [][]presentedCell {
+	failRow, failColumn, failed := g.FailedAt()
+
+	state := g.GetState()
+	board := make([][]presentedCell, len(state))
+	for i, stateRow := range state {
+		row := make([]presentedCell, len(stateRow))
+		for j, cell := range stateRow {
+			row[j] = presentCellPostMortem(cell, failed && i == failRow && j == failColumn)
+		}
+		board[i] = row
+	}
 
-	fmt.Fprint(ga.out, presentedBoard.String())
+	return board
 }
 
-func presentCellRevealed(c game.Cell) rune {
+func presentCellPostMortem(c game.Cell, detonated bool) presentedCell {
+	if detonated {
+		return presentedCell{kind: detonatedCell}
+	}
+
+	if c.State == game.AbsentState {
+		return presentedCell{kind: absentCell}
+	}
+
+	if c.State == game.FlaggedState {
+		if c.Content == game.BlackHoleCellValue {
+			return presentedCell{kind: flaggedCell}
+		}
+
+		return presentedCell{kind: wronglyFlaggedCell}
+	}
+
 	return convertCellValue(c.Content)
 }
 
-func presentCellAtGameTime(c game.Cell) rune {
+func presentCellAtGameTime(c game.PlayerCell) presentedCell {
 	switch c.State {
 	case game.HiddenState:
-		return 'H'
+		return presentedCell{kind: hiddenCell}
+	case game.FlaggedState:
+		return presentedCell{kind: flaggedCell}
 	case game.VisibleState:
 		return convertCellValue(c.Content)
+	case game.AbsentState:
+		return presentedCell{kind: absentCell}
 	default:
-		return '_'
-	}
-}
-
-func convertCellValue(v game.CellValue) rune {
-	switch v {
-	case game.BlackHoleCellValue:
-		return '*'
-	case game.ZeroCellValue:
-		return '0'
-	case game.OneCellValue:
-		return '1'
-	case game.TwoCellValue:
-		return '2'
-	case game.ThreeCellValue:
-		return '3'
-	case game.FourCellValue:
-		return '4'
-	case game.FiveCellValue:
-		return '5'
-	case game.SixCellValue:
-		return '6'
-	case game.SevenCellValue:
-		return '7'
-	case game.EightCellValue:
-		return '8'
+		return presentedCell{kind: unknownCell}
+	}
+}
+
+// convertCellValue presents numbers of any size, as masks may count more than
+// eight neighbours.
+func convertCellValue(v game.CellValue) presentedCell {
+	switch {
+	case v == game.BlackHoleCellValue:
+		return presentedCell{kind: blackHoleCell}
+	case v >= game.ZeroCellValue:
+		return presentedCell{kind: numberCell, number: int(v)}
 	default:
-		return '_'
+		return presentedCell{kind: unknownCell}
 	}
 }
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// maxMaskRadius limits radius masks, so numbers of cells stay readable.
+const maxMaskRadius = 5
+
+// parseMask parses the neighbourhood mask description. It is either "knight",
+// "radius:N" or a comma separated list of "row:column" offsets, for example
+// "-1:0,1:0,0:-1,0:1".
+func parseMask(s string) (game.Mask, error) {
+	if s == "knight" {
+		return game.KnightMask(), nil
+	}
+
+	if strings.HasPrefix(s, "radius:") {
+		r, err := strconv.Atoi(strings.TrimPrefix(s, "radius:"))
+		if err != nil || r < 1 || r > maxMaskRadius {
+			return game.Mask{}, fmt.Errorf("mask radius must be in [1, %d]", maxMaskRadius)
+		}
+
+		return game.RadiusMask(r), nil
+	}
+
+	var offsets []game.Offset
+	for _, field := range strings.Split(s, ",") {
+		row, column, ok := strings.Cut(strings.TrimSpace(field), ":")
+		if !ok {
+			return game.Mask{}, fmt.Errorf("mask offset %q must be row:column", field)
+		}
+
+		r, err := strconv.Atoi(row)
+		if err != nil {
+			return game.Mask{}, fmt.Errorf("mask offset %q: %w", field, err)
+		}
+		c, err := strconv.Atoi(column)
+		if err != nil {
+			return game.Mask{}, fmt.Errorf("mask offset %q: %w", field, err)
+		}
+
+		offsets = append(offsets, game.Offset{Row: r, Column: c})
+	}
+
+	mask := game.NewMask(offsets...)
+	if mask.MaxNeighbours() == 0 {
+		return game.Mask{}, errors.New("mask must have a non-zero offset")
+	}
+
+	return mask, nil
+}
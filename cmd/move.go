@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kalynv/proxx/game"
+)
+
+type action int
+
+const (
+	revealAction action = iota
+	flagAction
+	chordAction
+)
+
+func parseAction(s string) (action, error) {
+	switch s {
+	case "reveal":
+		return revealAction, nil
+	case "flag":
+		return flagAction, nil
+	case "chord":
+		return chordAction, nil
+	default:
+		return 0, fmt.Errorf("unknown action %q", s)
+	}
+}
+
+var (
+	errGameOver     = errors.New("the game is over")
+	errNoSuchCell   = errors.New("there is no such cell")
+	errCellVisible  = errors.New("the cell is already visible")
+	errCellFlagged  = errors.New("the cell is flagged, remove the flag first")
+	errCellNotShown = errors.New("the cell is not visible")
+	errGamePaused   = errors.New("the game is paused, resume it first")
+	errNotStarted   = errors.New("the game is not in progress")
+	errNotPaused    = errors.New("the game is not paused")
+)
+
+// validateMove returns an error describing why the game would panic on the
+// move. Otherwise nil is returned.
+func validateMove(g *game.Game, a action, row, column int) error {
+	if g.Completed() {
+		return errGameOver
+	}
+	if g.Status() == game.Paused {
+		return errGamePaused
+	}
+
+	cell, ok := g.PlayerCellAt(row, column)
+	if !ok {
+		return errNoSuchCell
+	}
+
+	switch a {
+	case revealAction:
+		if cell.State == game.VisibleState {
+			return errCellVisible
+		}
+		if cell.State == game.FlaggedState {
+			return errCellFlagged
+		}
+	case flagAction:
+		if cell.State == game.VisibleState {
+			return errCellVisible
+		}
+	case chordAction:
+		if cell.State != game.VisibleState {
+			return errCellNotShown
+		}
+	}
+
+	return nil
+}
+
+// applyMove validates the move and applies it to the game.
+func applyMove(g *game.Game, a action, row, column int) error {
+	if err := validateMove(g, a, row, column); err != nil {
+		return err
+	}
+
+	switch a {
+	case revealAction:
+		g.RevealCell(row, column)
+	case flagAction:
+		g.ToggleFlag(row, column)
+	case chordAction:
+		g.ChordCell(row, column)
+	}
+
+	return nil
+}
+
+// changeStatus validates the transition and pauses the game for game.Paused,
+// resumes or starts it for game.InProgress or resigns it for game.Resigned.
+func changeStatus(g *game.Game, to game.Status) error {
+	if !game.CanTransition(g.Status(), to) {
+		switch {
+		case g.Completed():
+			return errGameOver
+		case to == game.InProgress:
+			return errNotPaused
+		default:
+			return errNotStarted
+		}
+	}
+
+	switch to {
+	case game.Paused:
+		g.Pause()
+	case game.InProgress:
+		g.Resume()
+	case game.Resigned:
+		g.Resign()
+	default:
+		return fmt.Errorf("unknown status %s", to)
+	}
+
+	return nil
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cellKind is what the presented cell shows.
+type cellKind int
+
+const (
+	hiddenCell cellKind = iota
+	flaggedCell
+	numberCell
+	blackHoleCell
+	detonatedCell
+	wronglyFlaggedCell
+	absentCell
+	unknownCell
+)
+
+// presentedCell is the cell as presented to the player. Number is the count of
+// neighbouring black holes of number cells.
+type presentedCell struct {
+	kind   cellKind
+	number int
+}
+
+// text returns the ASCII symbol of the cell.
+func (c presentedCell) text() string {
+	switch c.kind {
+	case hiddenCell:
+		return "H"
+	case flaggedCell:
+		return "F"
+	case numberCell:
+		return strconv.Itoa(c.number)
+	case blackHoleCell:
+		return "*"
+	case detonatedCell:
+		return "@"
+	case wronglyFlaggedCell:
+		return "X"
+	case absentCell:
+		return " "
+	default:
+		return "_"
+	}
+}
+
+// boardView is the board to render: the displayed layer of cells titled by the
+// topology and layer.
+type boardView struct {
+	title string
+	cells [][]presentedCell
+	// wrap is set for boards whose edges wrap around
+	wrap bool
+	// hex is set for boards of hexagonal cells, whose odd rows are staggered
+	// by half a cell
+	hex bool
+	// width is the number of characters of the widest cell text
+	width int
+}
+
+// renderer writes boards in its own format.
+type renderer interface {
+	render(w io.Writer, b boardView)
+}
+
+var renderers = map[string]renderer{
+	"ascii": asciiRenderer{},
+	"box":   boxRenderer{},
+	"ansi":  ansiRenderer{},
+	"html":  htmlRenderer{},
+}
+
+func rendererNames() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func newRenderer(name string) (renderer, error) {
+	r, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer %q, want one of %s", name, strings.Join(rendererNames(), ", "))
+	}
+
+	return r, nil
+}
+
+// asciiRenderer renders cells as plain ASCII symbols right aligned to the
+// board width. Boards with wrapping edges are framed with '~' to show that
+// cells at opposite edges neighbour each other.
+type asciiRenderer struct{}
+
+func (asciiRenderer) render(w io.Writer, b boardView) {
+	writeTextBoard(w, b, func(c presentedCell) string {
+		return fmt.Sprintf("%*s", b.width, c.text())
+	})
+}
+
+// ansiRenderer renders the board of asciiRenderer with cells coloured by ANSI
+// escape codes, numbers in the colours of the classic game.
+type ansiRenderer struct{}
+
+func (ansiRenderer) render(w io.Writer, b boardView) {
+	writeTextBoard(w, b, func(c presentedCell) string {
+		text := fmt.Sprintf("%*s", b.width, c.text())
+		if code := ansiCode(c); code != "" {
+			return "\x1b[" + code + "m" + text + "\x1b[0m"
+		}
+
+		return text
+	})
+}
+
+// ansiNumberCodes are colours of numbers 1 to 8, larger numbers are bold.
+var ansiNumberCodes = []string{"34", "32", "31", "35", "33", "36", "37", "90"}
+
+func ansiCode(c presentedCell) string {
+	switch c.kind {
+	case hiddenCell:
+		return "2"
+	case flaggedCell:
+		return "1;33"
+	case numberCell:
+		switch {
+		case c.number == 0:
+			return "2"
+		case c.number <= len(ansiNumberCodes):
+			return ansiNumberCodes[c.number-1]
+		default:
+			return "1"
+		}
+	case blackHoleCell:
+		return "1"
+	case detonatedCell:
+		return "1;41"
+	case wronglyFlaggedCell:
+		return "1;31"
+	default:
+		return ""
+	}
+}
+
+// writeTextBoard writes the board one line per row with cells presented by
+// cell and separated by spaces.
+func writeTextBoard(w io.Writer, b boardView, cell func(c presentedCell) string) {
+	text := strings.Builder{}
+	text.WriteString("\n" + b.title + ":\n")
+
+	border := ""
+	if b.wrap && len(b.cells) > 0 {
+		border = strings.Repeat(fmt.Sprintf(" %*s", b.width, "~"), len(b.cells[0])+2) + "\n"
+	}
+	text.WriteString(border)
+
+	for i, row := range b.cells {
+		if b.wrap {
+			text.WriteString(fmt.Sprintf(" %*s", b.width, "~"))
+		}
+		if b.hex && i%2 == 1 {
+			text.WriteString(strings.Repeat(" ", (b.width+1)/2))
+		}
+		for _, c := range row {
+			text.WriteString(" " + cell(c))
+		}
+		if b.wrap {
+			text.WriteString(fmt.Sprintf(" %*s", b.width, "~"))
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString(border)
+	text.WriteString("\n")
+
+	fmt.Fprint(w, text.String())
+}
+
+// boxRenderer renders cells in a grid of Unicode box-drawing characters.
+// Only existing cells are boxed, absent cells are left blank. Staggered rows
+// of hexagonal boards are joined where their borders meet. Boards with
+// wrapping edges are framed with '~'.
+type boxRenderer struct{}
+
+// boxSymbols are symbols of cells other than numbers.
+var boxSymbols = map[cellKind]string{
+	hiddenCell:         "■",
+	flaggedCell:        "⚑",
+	blackHoleCell:      "✱",
+	detonatedCell:      "✸",
+	wronglyFlaggedCell: "✗",
+}
+
+// boxPoint is the point of a border line with lines going up, down, left and
+// right of it.
+type boxPoint struct {
+	up, down, left, right bool
+}
+
+func (boxRenderer) render(w io.Writer, b boardView) {
+	// cells take the width and a space at each side, bars take one more
+	pitch := b.width + 3
+	offset := func(i int) int {
+		if b.hex && i%2 == 1 {
+			return (pitch + 1) / 2
+		}
+
+		return 0
+	}
+
+	columns := 0
+	for i, row := range b.cells {
+		if n := offset(i) + len(row)*pitch + 1; n > columns {
+			columns = n
+		}
+	}
+
+	// borders[i] is the border above the row i, rows[i] is the row i
+	borders := make([][]boxPoint, len(b.cells)+1)
+	for i := range borders {
+		borders[i] = make([]boxPoint, columns)
+	}
+	rows := make([][]string, len(b.cells))
+	for i, row := range b.cells {
+		rows[i] = make([]string, columns)
+		for x := range rows[i] {
+			rows[i][x] = " "
+		}
+
+		for j, c := range row {
+			if c.kind == absentCell {
+				continue
+			}
+
+			left, right := offset(i)+j*pitch, offset(i)+(j+1)*pitch
+			for _, border := range [][]boxPoint{borders[i], borders[i+1]} {
+				border[left].right = true
+				for x := left + 1; x < right; x++ {
+					border[x].left, border[x].right = true, true
+				}
+				border[right].left = true
+			}
+			borders[i][left].down, borders[i][right].down = true, true
+			borders[i+1][left].up, borders[i+1][right].up = true, true
+
+			symbol, ok := boxSymbols[c.kind]
+			switch {
+			case c.kind == numberCell && c.number == 0:
+				symbol = "·"
+			case !ok:
+				symbol = c.text()
+			}
+			rows[i][left], rows[i][right] = "│", "│"
+			rows[i][left+1] = fmt.Sprintf(" %*s ", b.width, symbol)
+			for x := left + 2; x < right; x++ {
+				rows[i][x] = ""
+			}
+		}
+	}
+
+	lines := make([]string, 0, 2*len(b.cells)+1)
+	for i, border := range borders {
+		line := strings.Builder{}
+		for _, p := range border {
+			line.WriteRune(boxJunction(p))
+		}
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+
+		if i < len(rows) {
+			lines = append(lines, strings.TrimRight(strings.Join(rows[i], ""), " "))
+		}
+	}
+
+	text := strings.Builder{}
+	text.WriteString("\n" + b.title + ":\n")
+
+	width := 0
+	for _, line := range lines {
+		if n := len([]rune(line)); n > width {
+			width = n
+		}
+	}
+	if b.wrap {
+		text.WriteString(strings.Repeat("~", width+4) + "\n")
+	}
+	for i, line := range lines {
+		if b.wrap {
+			// rows of cells are marked, borders are only indented
+			side := "  "
+			if i%2 == 1 {
+				side = "~ "
+			}
+			line = side + line + strings.Repeat(" ", width-len([]rune(line))) + " " + strings.TrimSpace(side)
+			line = strings.TrimRight(line, " ")
+		}
+		text.WriteString(line + "\n")
+	}
+	if b.wrap {
+		text.WriteString(strings.Repeat("~", width+4) + "\n")
+	}
+	text.WriteString("\n")
+
+	fmt.Fprint(w, text.String())
+}
+
+// boxJunction returns the box-drawing character joining lines of the point,
+// or a space if no lines meet at it.
+func boxJunction(p boxPoint) rune {
+	switch {
+	case p.left && p.right && p.up && p.down:
+		return '┼'
+	case p.left && p.right && p.up:
+		return '┴'
+	case p.left && p.right && p.down:
+		return '┬'
+	case p.left && p.right:
+		return '─'
+	case p.right && p.up && p.down:
+		return '├'
+	case p.right && p.down:
+		return '┌'
+	case p.right && p.up:
+		return '└'
+	case p.left && p.up && p.down:
+		return '┤'
+	case p.left && p.down:
+		return '┐'
+	case p.left && p.up:
+		return '┘'
+	case p.up || p.down:
+		return '│'
+	default:
+		return ' '
+	}
+}
+
+// htmlRenderer renders the board as an HTML table. Cells are classed by what
+// they show, so pages style them. Odd rows of hexagonal boards are classed
+// "odd" to be staggered.
+type htmlRenderer struct{}
+
+// htmlCells are classes and contents of cells other than numbers.
+var htmlCells = map[cellKind][2]string{
+	hiddenCell:         {"hidden", ""},
+	flaggedCell:        {"flagged", "&#x2691;"},
+	blackHoleCell:      {"black-hole", "&#x2731;"},
+	detonatedCell:      {"detonated", "&#x2738;"},
+	wronglyFlaggedCell: {"wrongly-flagged", "&#x2717;"},
+	absentCell:         {"absent", ""},
+	unknownCell:        {"unknown", "?"},
+}
+
+func (htmlRenderer) render(w io.Writer, b boardView) {
+	classes := "board"
+	if b.wrap {
+		classes += " wrap"
+	}
+	if b.hex {
+		classes += " hex"
+	}
+
+	text := strings.Builder{}
+	text.WriteString(`<table class="` + classes + `">` + "\n")
+	text.WriteString("<caption>" + html.EscapeString(b.title) + "</caption>\n")
+	for i, row := range b.cells {
+		if b.hex && i%2 == 1 {
+			text.WriteString(`<tr class="odd">`)
+		} else {
+			text.WriteString("<tr>")
+		}
+		for _, c := range row {
+			class, content := htmlCells[c.kind][0], htmlCells[c.kind][1]
+			if c.kind == numberCell {
+				class = "number n" + strconv.Itoa(c.number)
+				if c.number > 0 {
+					content = strconv.Itoa(c.number)
+				}
+			}
+			text.WriteString(`<td class="` + class + `">` + content + "</td>")
+		}
+		text.WriteString("</tr>\n")
+	}
+	text.WriteString("</table>\n")
+
+	fmt.Fprint(w, text.String())
+}
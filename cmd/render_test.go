@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/kalynv/proxx/game/gametest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderers(t *testing.T) {
+	layered, err := game.NewGameFromLayout(6, 3, []game.Position{{Row: 0, Column: 0}, {Row: 4, Column: 2}},
+		game.WithTopology(game.NewGrid(2, 3, 3)))
+	require.NoError(t, err)
+	gametest.Play(t, layered, "reveal 3 0\nflag 4 2")
+
+	tests := []struct {
+		name       string
+		game       *game.Game
+		script     string
+		postMortem bool
+		layer      int
+	}{
+		{
+			name: "square",
+			game: gametest.NewGame(t, gametest.Lines("F1..*", "11..1", ".....")),
+		},
+		{
+			name:       "wrap_lost",
+			game:       gametest.NewGame(t, gametest.Lines("*...", "..*.", "...."), game.WithTopology(game.Torus{})),
+			script:     "flag 0 0\nflag 1 1\nreveal 1 2",
+			postMortem: true,
+		},
+		{
+			name:   "hex",
+			game:   gametest.NewGame(t, gametest.Lines("*....", ".....", "....*"), game.WithTopology(game.Hex{})),
+			script: "reveal 1 2",
+		},
+		{
+			name:       "shape_won",
+			game:       gametest.NewGame(t, gametest.Lines(" .* ", "....", " .. ")),
+			script:     "reveal 2 1",
+			postMortem: true,
+		},
+		{
+			name:   "hex_shape",
+			game:   gametest.NewGame(t, gametest.Lines(" ..*", "....", "... "), game.WithTopology(game.Hex{})),
+			script: "reveal 2 0",
+		},
+		{
+			name:   "radius_mask",
+			game:   gametest.NewGame(t, gametest.Lines("*****", "*****", "**.**", "*****", "*****"), game.WithTopology(game.RadiusMask(2))),
+			script: "reveal 2 2",
+		},
+		{
+			name:  "layer",
+			game:  layered,
+			layer: 1,
+		},
+	}
+	for _, tt := range tests {
+		gametest.Play(t, tt.game, tt.script)
+
+		for _, name := range rendererNames() {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				out := &bytes.Buffer{}
+				ga := newGameAdapter(tt.game, strings.NewReader(""), out)
+				ga.renderer = renderers[name]
+				ga.layer = tt.layer
+
+				if tt.postMortem {
+					ga.displayBoard(presentPostMortem(tt.game))
+				} else {
+					ga.displayBoard(presentPlayerBoard(tt.game.PlayerBoard()))
+				}
+
+				gametest.AssertGolden(t, filepath.Join("testdata", "render", tt.name+"."+name+".golden"), out.Bytes())
+			})
+		}
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	r, err := newRenderer("box")
+	require.NoError(t, err)
+	assert.Equal(t, boxRenderer{}, r)
+
+	_, err = newRenderer("svg")
+	assert.EqualError(t, err, `unknown renderer "svg", want one of ansi, ascii, box, html`)
+}
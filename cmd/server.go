@@ -0,0 +1,590 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+)
+
+const (
+	maxBoardSize   = 256
+	maxRequestBody = 1 << 10
+)
+
+// serve runs the HTTP JSON API server until it fails.
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	_ = flags.Parse(args)
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Serving proxx API on %s", *addr)
+	log.Fatal(httpServer.ListenAndServe())
+}
+
+// gameConfig describes a game to be created by the server.
+type gameConfig struct {
+	BoardSize  int    `json:"boardSize"`
+	BlackHoles int    `json:"blackHoles"`
+	Wrap       bool   `json:"wrap,omitempty"`
+	Hex        bool   `json:"hex,omitempty"`
+	Mask       string `json:"mask,omitempty"`
+}
+
+func (c gameConfig) validate() error {
+	if c.BoardSize < 1 || c.BoardSize > maxBoardSize {
+		return fmt.Errorf("boardSize must be in [1, %d]", maxBoardSize)
+	}
+	// games without safe cells are won before they start
+	if cells := c.BoardSize * c.BoardSize; c.BlackHoles < 0 || c.BlackHoles >= cells {
+		return fmt.Errorf("blackHoles must be in [0, %d]", cells-1)
+	}
+	if c.Wrap && c.Hex {
+		return errors.New("wrap and hex can not be combined")
+	}
+	if c.Mask != "" {
+		if c.Wrap || c.Hex {
+			return errors.New("mask can not be combined with wrap or hex")
+		}
+		if _, err := parseMask(c.Mask); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c gameConfig) options() []game.Option {
+	var options []game.Option
+	if c.Wrap {
+		options = append(options, game.WithTopology(game.Torus{}))
+	}
+	if c.Hex {
+		options = append(options, game.WithTopology(game.Hex{}))
+	}
+	if c.Mask != "" {
+		// the mask is parsed by validate
+		mask, _ := parseMask(c.Mask)
+		options = append(options, game.WithTopology(mask))
+	}
+
+	return options
+}
+
+type moveRequest struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+type position struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+type cellView struct {
+	State     string `json:"state"`
+	Value     *int   `json:"value,omitempty"`
+	BlackHole bool   `json:"blackHole,omitempty"`
+}
+
+type gameView struct {
+	ID     string       `json:"id"`
+	Status string       `json:"status"`
+	Board  [][]cellView `json:"board"`
+}
+
+type resultView struct {
+	ID       string       `json:"id"`
+	Status   string       `json:"status"`
+	FailedAt *position    `json:"failedAt,omitempty"`
+	Board    [][]cellView `json:"board"`
+}
+
+type errorView struct {
+	Error string `json:"error"`
+}
+
+const (
+	// gameTTL is how long games are kept since their last request.
+	gameTTL = time.Hour
+	// maxGames is the number of games kept at once.
+	maxGames = 10000
+	// maxStoredCells is the number of cells of all games kept at once. Games
+	// take about 64 bytes per cell with their change history, so stored games
+	// take no more than about 256 MiB.
+	maxStoredCells = 1 << 22
+)
+
+var errTooManyGames = errors.New("too many games, try again later")
+
+// gameStore keeps games in memory by their IDs. Games not requested for the
+// TTL are evicted, and no more than the maximum numbers of games and of their
+// cells are kept, so memory is bounded however many games clients create.
+type gameStore struct {
+	mu       sync.Mutex
+	games    map[string]*storedGame
+	cells    int
+	ttl      time.Duration
+	maxGames int
+	maxCells int
+	now      func() time.Time
+	// evicted is called with IDs of evicted games
+	evicted func(id string)
+}
+
+type storedGame struct {
+	game   *game.SyncGame
+	cells  int
+	usedAt time.Time
+}
+
+func newGameStore() *gameStore {
+	return &gameStore{
+		games:    make(map[string]*storedGame),
+		ttl:      gameTTL,
+		maxGames: maxGames,
+		maxCells: maxStoredCells,
+		now:      time.Now,
+		evicted:  func(string) {},
+	}
+}
+
+// add stores the game of the number of cells. errTooManyGames is returned if
+// the store is full of games in use.
+func (s *gameStore) add(g *game.SyncGame, cells int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evict(now)
+	if len(s.games) >= s.maxGames || s.cells+cells > s.maxCells {
+		return "", errTooManyGames
+	}
+
+	for {
+		id, err := newGameID()
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := s.games[id]; ok {
+			continue
+		}
+
+		s.games[id] = &storedGame{game: g, cells: cells, usedAt: now}
+		s.cells += cells
+
+		return id, nil
+	}
+}
+
+// get returns the game and keeps it for another TTL.
+func (s *gameStore) get(id string) (*game.SyncGame, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.games[id]
+	if !ok {
+		return nil, false
+	}
+
+	now := s.now()
+	if s.expired(stored, now) {
+		s.remove(id, stored)
+
+		return nil, false
+	}
+	stored.usedAt = now
+
+	return stored.game, true
+}
+
+// evict removes games not requested for the TTL.
+func (s *gameStore) evict(now time.Time) {
+	for id, stored := range s.games {
+		if s.expired(stored, now) {
+			s.remove(id, stored)
+		}
+	}
+}
+
+func (s *gameStore) remove(id string, stored *storedGame) {
+	delete(s.games, id)
+	s.cells -= stored.cells
+	s.evicted(id)
+}
+
+func (s *gameStore) expired(stored *storedGame, now time.Time) bool {
+	return now.Sub(stored.usedAt) >= s.ttl
+}
+
+func newGameID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// server exposes games over HTTP JSON API:
+//
+//	POST /games                 creates a game from gameConfig
+//	GET  /games/{id}            returns the game state with hidden cells masked
+//	POST /games/{id}/reveal     reveals the cell from moveRequest
+//	POST /games/{id}/flag       toggles the flag on the cell from moveRequest
+//	POST /games/{id}/chord      chords the cell from moveRequest
+//	GET  /games/{id}/result     returns the revealed board of the completed game
+//	GET  /games/{id}/events     streams game changes as server-sent events
+//
+// Games are kept in memory for an hour since their last request, and at most
+// maxGames of them of maxStoredCells cells at once. Creating games beyond that
+// is refused with 503. Event streams of evicted games end.
+type server struct {
+	store  *gameStore
+	events *eventBroker
+}
+
+func newServer() *server {
+	s := &server{
+		store:  newGameStore(),
+		events: newEventBroker(),
+	}
+	s.store.evicted = s.events.closeGame
+
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	if parts[0] != "games" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+
+		return
+	}
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+			return
+		}
+		s.createGame(w, r)
+
+		return
+	}
+
+	id := parts[1]
+	sg, ok := s.store.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("game not found"))
+
+		return
+	}
+
+	if len(parts) == 2 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+			return
+		}
+		var view gameView
+		sg.Read(func(g *game.Game) {
+			view = newGameView(id, g)
+		})
+		writeJSON(w, http.StatusOK, view)
+
+		return
+	}
+
+	if parts[2] == "result" || parts[2] == "events" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+			return
+		}
+		if parts[2] == "result" {
+			s.getResult(w, id, sg)
+		} else {
+			s.streamEvents(w, r, id, sg)
+		}
+
+		return
+	}
+
+	if to, ok := statusActions[parts[2]]; ok {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+			return
+		}
+		s.changeStatus(w, id, sg, to)
+
+		return
+	}
+
+	a, err := parseAction(parts[2])
+	if err != nil {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+
+		return
+	}
+	s.makeMove(w, r, id, sg, a)
+}
+
+func (s *server) createGame(w http.ResponseWriter, r *http.Request) {
+	var config gameConfig
+	if err := decodeJSON(r, &config); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	if err := config.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+
+		return
+	}
+
+	sg := game.NewSyncGame(game.NewGame(config.BoardSize, config.BlackHoles, config.options()...))
+	id, err := s.store.add(sg, config.BoardSize*config.BoardSize)
+	if errors.Is(err, errTooManyGames) {
+		writeError(w, http.StatusServiceUnavailable, err)
+
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	var view gameView
+	sg.Update(func(g *game.Game) {
+		g.Subscribe(func(e game.Event) {
+			if streamed, ok := newStreamEvent(e); ok {
+				s.events.publish(id, streamed)
+			}
+		})
+		view = newGameView(id, g)
+	})
+
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// makeMove applies the move while the game is locked, so concurrent moves are
+// validated against the latest state and their events are published in order.
+func (s *server) makeMove(w http.ResponseWriter, r *http.Request, id string, sg *game.SyncGame, a action) {
+	var move moveRequest
+	if err := decodeJSON(r, &move); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+
+		return
+	}
+
+	var after gameView
+	var err error
+	sg.Update(func(g *game.Game) {
+		if err = applyMove(g, a, move.Row, move.Column); err != nil {
+			return
+		}
+
+		after = newGameView(id, g)
+	})
+
+	if err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, errNoSuchCell) {
+			status = http.StatusUnprocessableEntity
+		}
+		writeError(w, status, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, after)
+}
+
+// statusActions maps paths of lifecycle actions to statuses they change games
+// to.
+var statusActions = map[string]game.Status{
+	"pause":  game.Paused,
+	"resume": game.InProgress,
+	"resign": game.Resigned,
+}
+
+func (s *server) changeStatus(w http.ResponseWriter, id string, sg *game.SyncGame, to game.Status) {
+	var after gameView
+	var err error
+	sg.Update(func(g *game.Game) {
+		if err = changeStatus(g, to); err != nil {
+			return
+		}
+
+		after = newGameView(id, g)
+	})
+
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, after)
+}
+
+func (s *server) getResult(w http.ResponseWriter, id string, sg *game.SyncGame) {
+	var result resultView
+	var completed bool
+	sg.Read(func(g *game.Game) {
+		completed = g.Completed()
+		if !completed {
+			return
+		}
+
+		result = resultView{
+			ID:     id,
+			Status: gameStatus(g),
+			Board:  newResultBoardView(g.GetState()),
+		}
+
+		if row, column, failed := g.FailedAt(); failed {
+			result.FailedAt = &position{Row: row, Column: column}
+		}
+	})
+
+	if !completed {
+		writeError(w, http.StatusConflict, errors.New("the game is in progress"))
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func newGameView(id string, g *game.Game) gameView {
+	return gameView{
+		ID:     id,
+		Status: gameStatus(g),
+		Board:  newPlayerBoardView(g.PlayerView()),
+	}
+}
+
+// newPlayerBoardView presents the board as seen by the player.
+func newPlayerBoardView(view [][]game.PlayerCell) [][]cellView {
+	rows := make([][]cellView, len(view))
+	for i, viewRow := range view {
+		row := make([]cellView, len(viewRow))
+		for j, cell := range viewRow {
+			row[j] = newCellView(cell.State, cell.Content)
+		}
+		rows[i] = row
+	}
+
+	return rows
+}
+
+// newResultBoardView presents the board with contents of all cells. It must
+// be used only for completed games.
+func newResultBoardView(board [][]game.Cell) [][]cellView {
+	rows := make([][]cellView, len(board))
+	for i, boardRow := range board {
+		row := make([]cellView, len(boardRow))
+		for j, cell := range boardRow {
+			row[j] = newCellView(cell.State, cell.Content)
+		}
+		rows[i] = row
+	}
+
+	return rows
+}
+
+// newCellView presents the cell for clients. The content is omitted if it is
+// game.UnknownCellValue.
+func newCellView(state game.CellState, content game.CellValue) cellView {
+	var view cellView
+
+	switch state {
+	case game.HiddenState:
+		view.State = "hidden"
+	case game.FlaggedState:
+		view.State = "flagged"
+	case game.VisibleState:
+		view.State = "visible"
+	case game.AbsentState:
+		view.State = "absent"
+
+		return view
+	}
+
+	switch content {
+	case game.UnknownCellValue:
+	case game.BlackHoleCellValue:
+		view.BlackHole = true
+	default:
+		value := int(content)
+		view.Value = &value
+	}
+
+	return view
+}
+
+func gameStatus(g *game.Game) string {
+	switch g.Status() {
+	case game.NotStarted:
+		return "not_started"
+	case game.Paused:
+		return "paused"
+	case game.Won:
+		return "won"
+	case game.Lost:
+		return "lost"
+	case game.Resigned:
+		return "resigned"
+	default:
+		return "in_progress"
+	}
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorView{Error: err.Error()})
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func doJSON(t *testing.T, method, url string, body interface{}, v interface{}) int {
+	t.Helper()
+
+	reader := bytes.NewReader(nil)
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if v != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+	}
+
+	return resp.StatusCode
+}
+
+func createTestGame(t *testing.T, url string, config gameConfig) gameView {
+	t.Helper()
+
+	var view gameView
+	status := doJSON(t, http.MethodPost, url+"/games", config, &view)
+	require.Equal(t, http.StatusCreated, status)
+
+	return view
+}
+
+func TestServer_createGame(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		body       interface{}
+		wantStatus int
+	}{
+		{
+			name:       "valid config",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "wrapped board",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Wrap: true},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "hexagonal board",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Hex: true},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "wrapped hexagonal board",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Wrap: true, Hex: true},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "knight mask",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Mask: "knight"},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "offsets mask",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Mask: "0:2,0:-2"},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "invalid mask",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Mask: "radius:x"},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "wrapped mask",
+			body:       gameConfig{BoardSize: 4, BlackHoles: 3, Wrap: true, Mask: "knight"},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "too many black holes",
+			body:       gameConfig{BoardSize: 2, BlackHoles: 5},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "no safe cells",
+			body:       gameConfig{BoardSize: 2, BlackHoles: 4},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "largest board",
+			body:       gameConfig{BoardSize: maxBoardSize, BlackHoles: maxBoardSize*maxBoardSize - 1, Wrap: true},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "board too large",
+			body:       gameConfig{BoardSize: maxBoardSize + 1, BlackHoles: 1},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "unknown field",
+			body:       map[string]int{"size": 3},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			var view gameView
+			status := doJSON(t, http.MethodPost, ts.URL+"/games", tt.body, &view)
+
+			assert.Equal(t, tt.wantStatus, status)
+			// black holes of the densest boards are placed in linear time
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func TestServer_stateMasksHiddenCells(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 3, BlackHoles: 8})
+
+	var view gameView
+	status := doJSON(t, http.MethodGet, ts.URL+"/games/"+created.ID, nil, &view)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "not_started", view.Status)
+	assert.Len(t, view.Board, 3)
+	for _, row := range view.Board {
+		assert.Len(t, row, 3)
+		for _, cell := range row {
+			assert.Equal(t, cellView{State: "hidden"}, cell)
+		}
+	}
+}
+
+func TestServer_moves(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 3, BlackHoles: 0})
+	gameURL := ts.URL + "/games/" + created.ID
+
+	var view gameView
+	status := doJSON(t, http.MethodPost, gameURL+"/flag", moveRequest{Row: 0, Column: 0}, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, cellView{State: "flagged"}, view.Board[0][0])
+
+	var errView errorView
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 0, Column: 0}, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errCellFlagged.Error(), errView.Error)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/chord", moveRequest{Row: 1, Column: 1}, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errCellNotShown.Error(), errView.Error)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 3, Column: 0}, &errView)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	status = doJSON(t, http.MethodGet, gameURL+"/result", nil, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 2, Column: 2}, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "in_progress", view.Status)
+	zero := 0
+	assert.Equal(t, cellView{State: "visible", Value: &zero}, view.Board[1][1])
+
+	status = doJSON(t, http.MethodPost, gameURL+"/flag", moveRequest{Row: 0, Column: 0}, &view)
+	assert.Equal(t, http.StatusOK, status)
+	status = doJSON(t, http.MethodPost, gameURL+"/chord", moveRequest{Row: 1, Column: 1}, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "won", view.Status)
+
+	var result resultView
+	status = doJSON(t, http.MethodGet, gameURL+"/result", nil, &result)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "won", result.Status)
+	assert.Nil(t, result.FailedAt)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 0, Column: 0}, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errGameOver.Error(), errView.Error)
+}
+
+func TestServer_lifecycle(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 3, BlackHoles: 1})
+	gameURL := ts.URL + "/games/" + created.ID
+
+	var view gameView
+	var errView errorView
+	status := doJSON(t, http.MethodPost, gameURL+"/pause", nil, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errNotStarted.Error(), errView.Error)
+
+	// resuming the game which is not started starts it
+	status = doJSON(t, http.MethodPost, gameURL+"/resume", nil, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "in_progress", view.Status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/flag", moveRequest{Row: 0, Column: 0}, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "in_progress", view.Status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/pause", nil, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "paused", view.Status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/flag", moveRequest{Row: 0, Column: 0}, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errGamePaused.Error(), errView.Error)
+
+	status = doJSON(t, http.MethodGet, gameURL+"/pause", nil, &errView)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/resume", nil, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "in_progress", view.Status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/resume", nil, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errNotPaused.Error(), errView.Error)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/resign", nil, &view)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "resigned", view.Status)
+
+	status = doJSON(t, http.MethodPost, gameURL+"/resign", nil, &errView)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, errGameOver.Error(), errView.Error)
+
+	var result resultView
+	status = doJSON(t, http.MethodGet, gameURL+"/result", nil, &result)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "resigned", result.Status)
+}
+
+func TestServer_result(t *testing.T) {
+	srv := newServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	// the only safe cell is the last one, so the first reveal loses the game
+	g, err := game.NewGameFromLayout(2, 2, []game.Position{{Row: 0, Column: 0}, {Row: 0, Column: 1}, {Row: 1, Column: 0}})
+	require.NoError(t, err)
+	id, err := srv.store.add(game.NewSyncGame(g), 4)
+	require.NoError(t, err)
+	gameURL := ts.URL + "/games/" + id
+
+	var result resultView
+	status := doJSON(t, http.MethodGet, gameURL+"/result", nil, &result)
+	assert.Equal(t, http.StatusConflict, status)
+
+	var view gameView
+	status = doJSON(t, http.MethodPost, gameURL+"/reveal", moveRequest{Row: 0, Column: 0}, &view)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "lost", view.Status)
+	assert.Equal(t, cellView{State: "hidden"}, view.Board[0][0])
+
+	status = doJSON(t, http.MethodGet, gameURL+"/result", nil, &result)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "lost", result.Status)
+	assert.Equal(t, &position{Row: 0, Column: 0}, result.FailedAt)
+	blackHoles := 0
+	for _, row := range result.Board {
+		for _, cell := range row {
+			if cell.BlackHole {
+				blackHoles++
+				assert.Nil(t, cell.Value)
+			} else {
+				assert.NotNil(t, cell.Value)
+			}
+		}
+	}
+	assert.Equal(t, 3, blackHoles)
+}
+
+func TestServer_routing(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 2, BlackHoles: 1})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "unknown game", method: http.MethodGet, path: "/games/unknown", wantStatus: http.StatusNotFound},
+		{name: "unknown path", method: http.MethodGet, path: "/players", wantStatus: http.StatusNotFound},
+		{name: "unknown action", method: http.MethodPost, path: "/games/" + created.ID + "/jump", wantStatus: http.StatusNotFound},
+		{name: "list games", method: http.MethodGet, path: "/games", wantStatus: http.StatusMethodNotAllowed},
+		{name: "reveal by get", method: http.MethodGet, path: "/games/" + created.ID + "/reveal", wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errView errorView
+			status := doJSON(t, tt.method, ts.URL+tt.path, nil, &errView)
+
+			assert.Equal(t, tt.wantStatus, status)
+			assert.NotEmpty(t, errView.Error)
+		})
+	}
+}
+
+func TestServer_concurrentMoves(t *testing.T) {
+	ts := httptest.NewServer(newServer())
+	defer ts.Close()
+
+	created := createTestGame(t, ts.URL, gameConfig{BoardSize: 10, BlackHoles: 10})
+	gameURL := ts.URL + "/games/" + created.ID
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for n := 0; n < 20; n++ {
+				move := moveRequest{Row: r.Intn(10), Column: r.Intn(10)}
+				path := []string{"/reveal", "/flag"}[r.Intn(2)]
+
+				status := doJSON(t, http.MethodPost, gameURL+path, move, nil)
+				if status != http.StatusOK && status != http.StatusConflict {
+					t.Errorf("Want status %d or %d, got %d", http.StatusOK, http.StatusConflict, status)
+				}
+
+				status = doJSON(t, http.MethodGet, gameURL, nil, nil)
+				if status != http.StatusOK {
+					t.Errorf("Want status %d, got %d", http.StatusOK, status)
+				}
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+}
+
+// FuzzServer_requests sends the game config and the move of request bodies.
+// Clients get errors of invalid requests, never server errors.
+func FuzzServer_requests(f *testing.F) {
+	f.Add([]byte(`{"boardSize": 3, "blackHoles": 2}`), "reveal", []byte(`{"row": 1, "column": 1}`))
+	f.Add([]byte(`{"boardSize": 8, "blackHoles": 10, "wrap": true}`), "chord", []byte(`{"row": 0, "column": 9}`))
+	f.Add([]byte(`{"boardSize": 5, "blackHoles": 0, "mask": "radius:2"}`), "flag", []byte(`{"row": -1}`))
+	f.Add([]byte(`{"boardSize": 4, "hex": true, "mask": "knight"}`), "resign", []byte(`null`))
+	f.Add([]byte(`{"boardSize": 1e3}`), "reveal", []byte(`{`))
+
+	f.Fuzz(func(t *testing.T, config []byte, action string, move []byte) {
+		// every input gets its own server, so games do not pile up
+		s := newServer()
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/games", bytes.NewReader(config)))
+		if rec.Code >= http.StatusInternalServerError {
+			t.Fatalf("config %q got %d: %s", config, rec.Code, rec.Body.String())
+		}
+		if rec.Code != http.StatusCreated {
+			return
+		}
+
+		var view gameView
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &view))
+
+		rec = httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/games/"+view.ID+"/"+url.PathEscape(action), bytes.NewReader(move)))
+		if rec.Code >= http.StatusInternalServerError {
+			t.Fatalf("%s of %q got %d: %s", action, move, rec.Code, rec.Body.String())
+		}
+	})
+}
+
+// fakeClock is the clock of the game store advanced by tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestGameStore_eviction(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	store := newGameStore()
+	store.now = clock.Now
+	store.maxGames = 2
+	var evicted []string
+	store.evicted = func(id string) { evicted = append(evicted, id) }
+
+	first, err := store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	require.NoError(t, err)
+	clock.advance(30 * time.Minute)
+	second, err := store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	require.NoError(t, err)
+
+	_, err = store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	assert.ErrorIs(t, err, errTooManyGames)
+
+	// requests keep games for another hour
+	clock.advance(20 * time.Minute)
+	_, ok := store.get(first)
+	assert.True(t, ok)
+	clock.advance(50 * time.Minute)
+	_, ok = store.get(first)
+	assert.True(t, ok)
+	_, ok = store.get(second)
+	assert.False(t, ok)
+	assert.Equal(t, []string{second}, evicted)
+
+	third, err := store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	require.NoError(t, err)
+	clock.advance(time.Hour)
+	_, err = store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	require.NoError(t, err)
+	for _, id := range []string{first, third} {
+		_, ok = store.get(id)
+		assert.False(t, ok, id)
+	}
+	assert.ElementsMatch(t, []string{second, first, third}, evicted)
+}
+
+func TestGameStore_cells(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	store := newGameStore()
+	store.now = clock.Now
+	store.maxCells = 10
+
+	_, err := store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	require.NoError(t, err)
+	_, err = store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	require.NoError(t, err)
+	_, err = store.add(game.NewSyncGame(game.NewGame(2, 1)), 4)
+	assert.ErrorIs(t, err, errTooManyGames)
+
+	// cells of evicted games are freed
+	clock.advance(time.Hour)
+	_, err = store.add(game.NewSyncGame(game.NewGame(3, 1)), 9)
+	require.NoError(t, err)
+	assert.Equal(t, 9, store.cells)
+}
+
+func TestServer_tooManyGames(t *testing.T) {
+	srv := newServer()
+	srv.store.maxGames = 1
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	createTestGame(t, ts.URL, gameConfig{BoardSize: 2, BlackHoles: 1})
+
+	var e errorView
+	status := doJSON(t, http.MethodPost, ts.URL+"/games", gameConfig{BoardSize: 2, BlackHoles: 1}, &e)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, errTooManyGames.Error(), e.Error)
+}
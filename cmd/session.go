@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// settings describe boards of the console session.
+type settings struct {
+	boardSize  int
+	blackHoles int
+	options    []game.Option
+	// seed of the next board, if boards are seeded
+	seed      *uint64
+	layers    int
+	shape     *game.Shape
+	boardFile string
+	code      string
+	share     bool
+	// renderer renders boards, boards are rendered in ASCII if nil
+	renderer renderer
+}
+
+// fixed returns true if the board is read from a file or a code, so every new
+// board is the same.
+func (s *settings) fixed() bool {
+	return s.boardFile != "" || s.code != ""
+}
+
+// newGame makes the board of the settings. Seeded settings advance to the next
+// seed, so every board of the session is different and yet reproducible.
+func (s *settings) newGame() (*game.Game, error) {
+	options := s.options
+	if s.seed != nil {
+		options = append(options[:len(options):len(options)], game.WithSeed(*s.seed))
+		*s.seed++
+	}
+
+	var g *game.Game
+	switch {
+	case s.code != "":
+		var err error
+		g, err = game.NewGameFromCode(s.code)
+		if err != nil {
+			return nil, err
+		}
+	case s.boardFile != "":
+		var err error
+		g, err = readBoard(s.boardFile, options)
+		if err != nil {
+			return nil, err
+		}
+	case s.shape != nil:
+		// keep the density of black holes of the square board
+		g = game.NewShapedGame(*s.shape, s.shape.Cells()*s.blackHoles/(s.boardSize*s.boardSize), options...)
+	case s.layers != 0:
+		g = game.NewGridGame(game.NewGrid(s.layers, s.boardSize, s.boardSize), s.blackHoles*s.layers, options...)
+	default:
+		g = game.NewGame(s.boardSize, s.blackHoles, options...)
+	}
+
+	return g, nil
+}
+
+// sessionStats is the cumulative result of games played in the session.
+type sessionStats struct {
+	games int
+	wins  int
+	// best is the shortest time of won games
+	best time.Duration
+}
+
+func (s *sessionStats) add(g *game.Game) {
+	s.games++
+	if !g.Won() {
+		return
+	}
+
+	s.wins++
+	if s.wins == 1 || g.Elapsed() < s.best {
+		s.best = g.Elapsed()
+	}
+}
+
+func (s sessionStats) String() string {
+	best := "-"
+	if s.wins > 0 {
+		best = s.best.Round(time.Second).String()
+	}
+
+	return fmt.Sprintf("Games: %d, wins: %d, best time: %s", s.games, s.wins, best)
+}
+
+// errQuit is returned when the player ends the session.
+var errQuit = errors.New("quit")
+
+// playSession plays games one after another until the player quits. Players
+// may replay the same board, play a new board of the same settings or change
+// the settings between games.
+func playSession(s *settings, in io.Reader, out io.Writer) error {
+	g, err := s.newGame()
+	if err != nil {
+		return err
+	}
+
+	ga := newGameAdapter(g, in, out)
+	if s.renderer != nil {
+		ga.renderer = s.renderer
+	}
+	stats := sessionStats{}
+	for {
+		if s.share {
+			shareCode, err := game.ShareCode(ga.game)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(ga.out, "Board code: %s\n", shareCode)
+		}
+
+		ga.Play()
+		stats.add(ga.game)
+		fmt.Fprintln(ga.out, stats.String())
+
+		next, err := ga.readNext(s)
+		if errors.Is(err, errQuit) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ga.game = next
+		ga.layer = 0
+	}
+}
+
+// readNext reads what the player wants to play after the game is over and
+// returns the game to play. errQuit is returned if the player quits.
+func (ga *gameAdapter) readNext(s *settings) (*game.Game, error) {
+	for {
+		fmt.Fprint(ga.out, "Play again? (r - replay the board, n - new board, s - change settings, q - quit): ")
+		line, err := readLine(ga.in)
+		if errors.Is(err, io.EOF) {
+			return nil, errQuit
+		}
+		if err != nil {
+			fmt.Fprintf(ga.out, "%s\n", err.Error())
+
+			continue
+		}
+
+		switch line {
+		case "r":
+			return ga.game.Restart(), nil
+		case "n", "":
+			if s.fixed() {
+				fmt.Fprintln(ga.out, "the board is fixed, replay it or change settings")
+
+				continue
+			}
+
+			return s.newGame()
+		case "s":
+			if err := ga.readSettings(s); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil, errQuit
+				}
+
+				return nil, err
+			}
+
+			return s.newGame()
+		case "q":
+			return nil, errQuit
+		default:
+			fmt.Fprintf(ga.out, "unknown choice %q\n", line)
+		}
+	}
+}
+
+// readSettings reads the board size and the number of black holes. Boards of
+// files and codes are replaced with random boards of the read settings.
+func (ga *gameAdapter) readSettings(s *settings) error {
+	boardSize, err := ga.readNumber("Enter board size", 1, maxBoardSize)
+	if err != nil {
+		return err
+	}
+	blackHoles, err := ga.readNumber("Enter black holes", 0, boardSize*boardSize)
+	if err != nil {
+		return err
+	}
+
+	s.boardSize = boardSize
+	s.blackHoles = blackHoles
+	s.boardFile = ""
+	s.code = ""
+
+	return nil
+}
+
+// readNumber reads the number in [min, max] until a valid one is entered.
+// Only the end of input is returned as an error.
+func (ga *gameAdapter) readNumber(prompt string, min, max int) (int, error) {
+	for {
+		fmt.Fprintf(ga.out, "%s [%d-%d]: ", prompt, min, max)
+		n, err := readInt(ga.in)
+		if errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if err != nil {
+			fmt.Fprintf(ga.out, "%s\n", err.Error())
+
+			continue
+		}
+		if n < min || n > max {
+			fmt.Fprintf(ga.out, "%d is out of [%d, %d]\n", n, min, max)
+
+			continue
+		}
+
+		return n, nil
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaySession(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings settings
+		input    string
+		want     []string
+	}{
+		{
+			name:     "new boards and settings",
+			settings: settings{boardSize: 1, blackHoles: 0},
+			input:    "r\n0\n0\nn\nr\n0\n0\ns\n0\n1\n1\nr\nx\nq\n",
+			want: []string{
+				"Games: 1, wins: 1, best time: 0s",
+				"Games: 2, wins: 2, best time: 0s",
+				"0 is out of [1, 256]",
+				"Games: 3, wins: 3, best time: 0s",
+				"Games: 4, wins: 4, best time: 0s",
+				`unknown choice "x"`,
+			},
+		},
+		{
+			name:     "fixed board is replayed",
+			settings: settings{boardFile: "boards/corners.txt"},
+			input:    "r\n0\n0\nn\nr\nq\n",
+			want: []string{
+				"You lost.",
+				"Games: 1, wins: 0, best time: -",
+				"the board is fixed, replay it or change settings",
+				"You resigned.",
+				"Games: 2, wins: 0, best time: -",
+			},
+		},
+		{
+			name:     "closed input abandons the game",
+			settings: settings{boardSize: 3, blackHoles: 2},
+			input:    "r\n0",
+			want:     []string{"You resigned.", "Games: 1, wins: 0, best time: -"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			s := tt.settings
+
+			require.NoError(t, playSession(&s, strings.NewReader(tt.input), out))
+
+			// the expected lines follow each other in the output
+			rest := out.String()
+			for _, line := range tt.want {
+				i := strings.Index(rest, line)
+				require.NotEqual(t, -1, i, "%q is missing in\n%s", line, out.String())
+				rest = rest[i+len(line):]
+			}
+		})
+	}
+}
+
+func TestSessionStats_add(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	won := func(d time.Duration) *game.Game {
+		g, err := game.NewGameFromLayout(1, 2, []game.Position{{Row: 0, Column: 0}}, game.WithClock(clock.Now))
+		require.NoError(t, err)
+		g.Resume()
+		clock.advance(d)
+		g.RevealCell(0, 1)
+		require.True(t, g.Won())
+
+		return g
+	}
+
+	// a slower win does not replace the instant first one
+	var stats sessionStats
+	stats.add(won(0))
+	stats.add(won(3 * time.Second))
+	assert.Equal(t, sessionStats{games: 2, wins: 2}, stats)
+
+	stats = sessionStats{}
+	stats.add(won(2 * time.Second))
+	stats.add(won(time.Second))
+	assert.Equal(t, sessionStats{games: 2, wins: 2, best: time.Second}, stats)
+}
+
+func TestSettings_newGame_seed(t *testing.T) {
+	seed := uint64(42)
+	s := &settings{boardSize: 8, blackHoles: 10, seed: &seed}
+
+	first, err := s.newGame()
+	require.NoError(t, err)
+	second, err := s.newGame()
+	require.NoError(t, err)
+
+	got, _ := first.Seed()
+	assert.Equal(t, uint64(42), got)
+	got, _ = second.Seed()
+	assert.Equal(t, uint64(43), got)
+	assert.Equal(t, uint64(44), seed)
+}
+
+func TestSettings_newGame_seedLayers(t *testing.T) {
+	boards := make([]string, 2)
+	for i := range boards {
+		seed := uint64(42)
+		s := &settings{boardSize: 4, blackHoles: 3, seed: &seed, layers: 3}
+
+		g, err := s.newGame()
+		require.NoError(t, err)
+		boards[i] = game.FormatBoard(g)
+	}
+
+	assert.Equal(t, boards[0], boards[1])
+}
@@ -0,0 +1,132 @@
+package game
+
+import "sort"
+
+// Board is a flat snapshot of the game as seen by the player. Cells are stored
+// row by row in a single slice.
+type Board struct {
+	Rows    int
+	Columns int
+	Version uint64
+	Cells   []PlayerCell
+}
+
+// At returns the cell at i row and j column.
+func (b Board) At(i, j int) PlayerCell {
+	return b.Cells[i*b.Columns+j]
+}
+
+// Apply updates the board with changes made up to version.
+func (b *Board) Apply(changes []CellChange, version uint64) {
+	for _, c := range changes {
+		b.Cells[c.Row*b.Columns+c.Column] = c.Cell
+	}
+	b.Version = version
+}
+
+// CellChange is the current cell as seen by the player at Row and Column.
+type CellChange struct {
+	Row    int
+	Column int
+	Cell   PlayerCell
+}
+
+// changeRecord notes that the cell changed at the version.
+type changeRecord struct {
+	version uint64
+	address cellAddress
+}
+
+// Version returns the version of the game state. The version is increased
+// by every move changing the game.
+func (g *Game) Version() uint64 {
+	return g.version
+}
+
+// PlayerBoard returns the current Game state as seen by the player in a single
+// allocation.
+func (g *Game) PlayerBoard() Board {
+	columns := 0
+	if len(g.board) > 0 {
+		columns = len(g.board[0])
+	}
+
+	board := Board{
+		Rows:    len(g.board),
+		Columns: columns,
+		Version: g.version,
+		Cells:   make([]PlayerCell, len(g.board)*columns),
+	}
+	for i, row := range g.board {
+		cells := board.Cells[i*columns : (i+1)*columns]
+		for j, cell := range row {
+			cells[j] = newPlayerCell(cell)
+		}
+	}
+
+	return board
+}
+
+// ChangesSince returns cells changed after the version as seen by the player
+// and the current version. Each cell is listed once.
+func (g *Game) ChangesSince(version uint64) (changes []CellChange, current uint64) {
+	start := sort.Search(len(g.changes), func(i int) bool {
+		return g.changes[i].version > version
+	})
+
+	seen := make(map[cellAddress]struct{}, len(g.changes)-start)
+	changes = make([]CellChange, 0, len(g.changes)-start)
+	for _, record := range g.changes[start:] {
+		if _, ok := seen[record.address]; ok {
+			continue
+		}
+		seen[record.address] = struct{}{}
+
+		changes = append(changes, CellChange{
+			Row:    record.address.row,
+			Column: record.address.column,
+			Cell:   newPlayerCell(*getCell(g.board, record.address)),
+		})
+	}
+
+	return changes, g.version
+}
+
+// record increases the game version and notes changed cells. Once records
+// outnumber cells of the board twice, records of cells changed again later
+// are dropped, so the history is bounded by the board however many moves are
+// made.
+func (g *Game) record(changed ...cellAddress) {
+	g.version++
+	for _, a := range changed {
+		g.changes = append(g.changes, changeRecord{version: g.version, address: a})
+	}
+
+	cells := 0
+	for _, row := range g.board {
+		cells += len(row)
+	}
+	if len(g.changes) > 2*cells {
+		g.compactChanges()
+	}
+}
+
+// compactChanges keeps the latest record of every cell. ChangesSince lists
+// changed cells by their current state, so earlier records of the same cell
+// add nothing.
+func (g *Game) compactChanges() {
+	latest := make(map[cellAddress]struct{}, len(g.changes))
+	kept := len(g.changes)
+	for i := len(g.changes) - 1; i >= 0; i-- {
+		record := g.changes[i]
+		if _, ok := latest[record.address]; ok {
+			continue
+		}
+		latest[record.address] = struct{}{}
+
+		kept--
+		g.changes[kept] = record
+	}
+
+	g.changes = append(g.changes[:0], g.changes[kept:]...)
+}
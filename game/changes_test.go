@@ -0,0 +1,160 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_ChangesSince(t *testing.T) {
+	board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
+	blackHoleAddresses := []cellAddress{
+		{row: 0, column: 0},
+		{row: 2, column: 0},
+	}
+	replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, Square{})
+	game := &Game{failAt: nil, board: board}
+
+	initial := game.PlayerBoard()
+	assert.Equal(t, uint64(0), initial.Version)
+
+	game.ToggleFlag(0, 0)
+	afterFlag := game.Version()
+	game.RevealCell(1, 0)
+	game.ToggleFlag(0, 0)
+
+	changes, version := game.ChangesSince(afterFlag)
+	assert.Equal(t, uint64(3), version)
+	assert.Equal(t, []CellChange{
+		{Row: 1, Column: 0, Cell: PlayerCell{Content: TwoCellValue, State: VisibleState}},
+		{Row: 0, Column: 0, Cell: PlayerCell{Content: UnknownCellValue, State: HiddenState}},
+	}, changes)
+
+	changes, version = game.ChangesSince(initial.Version)
+	assert.Equal(t, uint64(3), version)
+	assert.Equal(t, []CellChange{
+		{Row: 0, Column: 0, Cell: PlayerCell{Content: UnknownCellValue, State: HiddenState}},
+		{Row: 1, Column: 0, Cell: PlayerCell{Content: TwoCellValue, State: VisibleState}},
+	}, changes)
+
+	initial.Apply(changes, version)
+	assert.Equal(t, game.PlayerBoard(), initial)
+
+	changes, version = game.ChangesSince(version)
+	assert.Empty(t, changes)
+	assert.Equal(t, uint64(3), version)
+
+	game.RevealCell(2, 0)
+	changes, version = game.ChangesSince(3)
+	assert.Empty(t, changes)
+	assert.Equal(t, uint64(4), version)
+}
+
+func TestGame_PlayerBoard(t *testing.T) {
+	game := &Game{
+		failAt: nil,
+		board: [][]Cell{
+			{{Content: BlackHoleCellValue, State: FlaggedState}, {Content: OneCellValue, State: VisibleState}},
+			{{Content: OneCellValue, State: HiddenState}, {Content: OneCellValue, State: VisibleState}},
+		},
+	}
+
+	got := game.PlayerBoard()
+
+	assert.Equal(t, 2, got.Rows)
+	assert.Equal(t, 2, got.Columns)
+	view := game.PlayerView()
+	for i := range view {
+		for j := range view[i] {
+			assert.Equal(t, view[i][j], got.At(i, j))
+		}
+	}
+}
+
+// newBenchmarkGame returns the game with every 10th cell a black hole. The
+// layout is fixed, as placing black holes randomly on large boards takes
+// longer than benchmarks.
+func TestGame_ChangesSince_bounded(t *testing.T) {
+	game := parseBoard(t, "*.\n..\n")
+	board := game.PlayerBoard()
+
+	game.ToggleFlag(1, 1)
+	afterFlag := game.Version()
+	for i := 0; i < 1000; i++ {
+		game.ToggleFlag(0, 0)
+	}
+	assert.LessOrEqual(t, len(game.changes), 8)
+
+	changes, version := game.ChangesSince(afterFlag)
+	assert.Equal(t, uint64(1001), version)
+	assert.Equal(t, []CellChange{
+		{Row: 0, Column: 0, Cell: PlayerCell{Content: UnknownCellValue, State: HiddenState}},
+	}, changes)
+
+	changes, version = game.ChangesSince(board.Version)
+	assert.Len(t, changes, 2)
+	board.Apply(changes, version)
+	assert.Equal(t, game.PlayerBoard(), board)
+}
+
+func newBenchmarkGame(size int) *Game {
+	blackHoles := make([]Position, 0, size*size/10)
+	for i := 0; i < size*size; i += 10 {
+		blackHoles = append(blackHoles, Position{Row: i / size, Column: i % size})
+	}
+
+	game, err := NewGameFromLayout(size, size, blackHoles)
+	if err != nil {
+		panic(err)
+	}
+
+	return game
+}
+
+func BenchmarkGame_GetState(b *testing.B) {
+	game := newBenchmarkGame(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		game.GetState()
+	}
+}
+
+func BenchmarkGame_PlayerView(b *testing.B) {
+	game := newBenchmarkGame(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		game.PlayerView()
+	}
+}
+
+func BenchmarkGame_PlayerBoard(b *testing.B) {
+	game := newBenchmarkGame(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		game.PlayerBoard()
+	}
+}
+
+// BenchmarkGame_ChangesSince measures a client following the game with the
+// delta of a move. The move is made once, as moves check the whole board for
+// the end of the game, which would take longer than the benchmark.
+func BenchmarkGame_ChangesSince(b *testing.B) {
+	game := newBenchmarkGame(1000)
+	board := game.PlayerBoard()
+	before := board.Version
+	game.ToggleFlag(0, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		changes, version := game.ChangesSince(before)
+		board.Apply(changes, version)
+	}
+}
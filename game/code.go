@@ -0,0 +1,124 @@
+package game
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// codeVersion is the first byte of share codes, so the encoding may change.
+const codeVersion = 1
+
+// Topologies of share codes.
+const (
+	codeSquare byte = iota
+	codeTorus
+	codeHex
+)
+
+// ErrInvalidCode is returned for share codes which can not be decoded.
+var ErrInvalidCode = errors.New("invalid board code")
+
+// ShareCode returns the compact URL-safe code of the board layout. The code
+// holds the topology, dimensions and black holes of the board, but not the
+// progress of the game.
+// Only boards of Square, Torus and Hex topologies without absent cells can be
+// shared.
+func ShareCode(g *Game) (string, error) {
+	var topology byte
+	switch g.Topology().(type) {
+	case Square:
+		topology = codeSquare
+	case Torus:
+		topology = codeTorus
+	case Hex:
+		topology = codeHex
+	default:
+		return "", fmt.Errorf("boards of %T topology can not be shared", g.Topology())
+	}
+
+	rows := len(g.board)
+	columns := 0
+	if rows > 0 {
+		columns = len(g.board[0])
+	}
+
+	code := []byte{codeVersion, topology}
+	code = binary.AppendUvarint(code, uint64(rows))
+	code = binary.AppendUvarint(code, uint64(columns))
+
+	bitmap := make([]byte, (rows*columns+7)/8)
+	for i, row := range g.board {
+		for j, cell := range row {
+			if cell.State == AbsentState {
+				return "", errors.New("irregular boards can not be shared")
+			}
+			if cell.Content == BlackHoleCellValue {
+				n := i*columns + j
+				bitmap[n/8] |= 1 << (n % 8)
+			}
+		}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(append(code, bitmap...)), nil
+}
+
+// NewGameFromCode returns the new game of the board encoded by ShareCode.
+// Errors wrapping ErrInvalidCode are returned for malformed codes.
+func NewGameFromCode(code string) (*Game, error) {
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCode, err)
+	}
+
+	if len(data) < 2 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidCode)
+	}
+	header, r := data[:2], bytes.NewReader(data[2:])
+	if header[0] != codeVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidCode, header[0])
+	}
+
+	var topology Topology
+	switch header[1] {
+	case codeSquare:
+		topology = Square{}
+	case codeTorus:
+		topology = Torus{}
+	case codeHex:
+		topology = Hex{}
+	default:
+		return nil, fmt.Errorf("%w: unknown topology %d", ErrInvalidCode, header[1])
+	}
+
+	rows, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: rows: %v", ErrInvalidCode, err)
+	}
+	columns, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: columns: %v", ErrInvalidCode, err)
+	}
+
+	// the bitmap bounds dimensions, so huge boards are not allocated
+	bitmap := data[len(data)-r.Len():]
+	cells := uint64(len(bitmap)) * 8
+	if (rows == 0) != (columns == 0) || (rows != 0 && columns > cells/rows) || (rows*columns+7)/8 != uint64(len(bitmap)) {
+		return nil, fmt.Errorf("%w: %dx%d board does not match %d bytes of black holes", ErrInvalidCode, rows, columns, len(bitmap))
+	}
+
+	var blackHoles []Position
+	for n := uint64(0); n < cells; n++ {
+		if bitmap[n/8]&(1<<(n%8)) == 0 {
+			continue
+		}
+		if n >= rows*columns {
+			return nil, fmt.Errorf("%w: black hole out of the board", ErrInvalidCode)
+		}
+		blackHoles = append(blackHoles, Position{Row: int(n / columns), Column: int(n % columns)})
+	}
+
+	return NewGameFromLayout(int(rows), int(columns), blackHoles, WithTopology(topology))
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		board   string
+		options []Option
+	}{
+		{
+			name:  "square",
+			board: "*..\n.*.\n..*\n",
+		},
+		{
+			name:    "torus",
+			board:   "*...\n....\n",
+			options: []Option{WithTopology(Torus{})},
+		},
+		{
+			name:    "hex",
+			board:   ".*\n..\n*.\n",
+			options: []Option{WithTopology(Hex{})},
+		},
+		{
+			name:  "empty",
+			board: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := parseBoard(t, tt.board, tt.options...)
+
+			code, err := ShareCode(g)
+			require.NoError(t, err)
+			got, err := NewGameFromCode(code)
+			require.NoError(t, err)
+
+			assert.Equal(t, g.GetState(), got.GetState())
+			assert.Equal(t, g.Topology(), got.Topology())
+		})
+	}
+}
+
+func TestShareCode_length(t *testing.T) {
+	code, err := ShareCode(NewGame(16, 40))
+	require.NoError(t, err)
+
+	// 4 bytes of header and 32 bytes of black holes
+	assert.Len(t, code, 48)
+}
+
+func TestShareCode_unsupported(t *testing.T) {
+	_, err := ShareCode(NewGame(3, 1, WithTopology(KnightMask())))
+	assert.Error(t, err)
+
+	_, err = ShareCode(parseBoard(t, "*.\n.\n"))
+	assert.Error(t, err)
+}
+
+func TestNewGameFromCode_invalid(t *testing.T) {
+	encode := func(data ...byte) string {
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	codes := map[string]string{
+		"not base64":         "***",
+		"too short":          encode(codeVersion),
+		"unknown version":    encode(9, codeSquare, 1, 1, 0),
+		"unknown topology":   encode(codeVersion, 9, 1, 1, 0),
+		"missing columns":    encode(codeVersion, codeSquare, 1),
+		"short bitmap":       encode(codeVersion, codeSquare, 3, 3, 0),
+		"long bitmap":        encode(codeVersion, codeSquare, 1, 1, 0, 0),
+		"rows without cells": encode(codeVersion, codeSquare, 0xff, 0xff, 0xff, 0x7f, 0),
+		"black hole padding": encode(codeVersion, codeSquare, 1, 1, 0x02),
+	}
+	for name, code := range codes {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewGameFromCode(code)
+
+			assert.ErrorIs(t, err, ErrInvalidCode)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package game
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+// DailySeed returns the seed of the board of the day for the preset. The day
+// is taken in UTC, so players in all time zones get the same board on the same
+// date.
+func DailySeed(date time.Time, preset string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(date.UTC().Format("2006-01-02")))
+	h.Write([]byte{0})
+	h.Write([]byte(preset))
+
+	return h.Sum64()
+}
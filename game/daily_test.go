@@ -0,0 +1,22 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailySeed(t *testing.T) {
+	date := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	// seeds must never change, as recorded daily results refer to them
+	assert.Equal(t, uint64(16598911566453570571), DailySeed(date, "beginner"))
+	assert.Equal(t, uint64(2706760925808993301), DailySeed(date, "expert"))
+
+	// the same UTC day in other time zones and times of the day
+	kyiv := time.FixedZone("Kyiv", 3*60*60)
+	assert.Equal(t, DailySeed(date, "beginner"), DailySeed(time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC), "beginner"))
+	assert.Equal(t, DailySeed(date, "beginner"), DailySeed(time.Date(2026, 10, 19, 2, 0, 0, 0, kyiv), "beginner"))
+	assert.NotEqual(t, DailySeed(date, "beginner"), DailySeed(date.AddDate(0, 0, 1), "beginner"))
+}
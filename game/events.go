@@ -0,0 +1,107 @@
+package game
+
+// Event describes a change of the game state. It is one of CellRevealed,
+// CellFlagged, GameWon, GameLost, GamePaused, GameResumed or GameResigned.
+type Event interface {
+	event()
+}
+
+// RevealedCell is a cell which became visible.
+type RevealedCell struct {
+	Row     int
+	Column  int
+	Content CellValue
+}
+
+// CellRevealed is emitted when cells become visible. Cells lists the revealed
+// cell followed by cells opened with it as contiguous space of empty cells.
+// When a cell is chorded, Cells lists all cells opened by the chord.
+type CellRevealed struct {
+	Cells []RevealedCell
+}
+
+// CellFlagged is emitted when a flag is put on or removed from a cell.
+type CellFlagged struct {
+	Row     int
+	Column  int
+	Flagged bool
+}
+
+// GameWon is emitted when all cells except of black holes become visible.
+type GameWon struct{}
+
+// GameLost is emitted when a black hole at Row and Column is revealed.
+type GameLost struct {
+	Row    int
+	Column int
+}
+
+// GamePaused is emitted when the game is paused.
+type GamePaused struct{}
+
+// GameResumed is emitted when the paused game is resumed.
+type GameResumed struct{}
+
+// GameResigned is emitted when the game is resigned.
+type GameResigned struct{}
+
+func (CellRevealed) event() {}
+func (CellFlagged) event()  {}
+func (GameWon) event()      {}
+func (GameLost) event()     {}
+func (GamePaused) event()   {}
+func (GameResumed) event()  {}
+func (GameResigned) event() {}
+
+type subscriber struct {
+	handle func(e Event)
+}
+
+// Subscribe registers handler to be called with every event of the game.
+// Handlers are called synchronously after the game state is updated, in the
+// order they were subscribed. Handlers must not modify the game.
+// Subscribe returns a function removing the handler.
+func (g *Game) Subscribe(handler func(e Event)) (unsubscribe func()) {
+	s := &subscriber{handle: handler}
+	g.subscribers = append(g.subscribers, s)
+
+	return func() {
+		for i, subscribed := range g.subscribers {
+			if subscribed == s {
+				g.subscribers = append(g.subscribers[:i:i], g.subscribers[i+1:]...)
+
+				return
+			}
+		}
+	}
+}
+
+func (g *Game) emit(events ...Event) {
+	for _, e := range events {
+		for _, s := range g.subscribers {
+			s.handle(e)
+		}
+	}
+}
+
+// revealEvents returns events describing revealed cells and the game outcome
+// if the game got completed.
+func (g *Game) revealEvents(revealed []cellAddress) []Event {
+	events := make([]Event, 0, 2)
+
+	if len(revealed) > 0 {
+		cells := make([]RevealedCell, len(revealed))
+		for i, a := range revealed {
+			cells[i] = RevealedCell{Row: a.row, Column: a.column, Content: getCell(g.board, a).Content}
+		}
+		events = append(events, CellRevealed{Cells: cells})
+	}
+
+	if g.failAt != nil {
+		events = append(events, GameLost{Row: g.failAt.row, Column: g.failAt.column})
+	} else if g.won() {
+		events = append(events, GameWon{})
+	}
+
+	return events
+}
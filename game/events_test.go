@@ -0,0 +1,129 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Subscribe(t *testing.T) {
+	newGame := func() *Game {
+		g, err := NewGameFromLayout(3, 3, []Position{{Row: 0, Column: 0}, {Row: 2, Column: 0}})
+		require.NoError(t, err)
+
+		return g
+	}
+
+	tests := []struct {
+		name       string
+		invoke     func(g *Game)
+		wantEvents []Event
+	}{
+		{
+			name: "reveal cell",
+			invoke: func(g *Game) {
+				g.RevealCell(1, 0)
+			},
+			wantEvents: []Event{
+				CellRevealed{Cells: []RevealedCell{{Row: 1, Column: 0, Content: TwoCellValue}}},
+			},
+		},
+		{
+			name: "open contiguous space and win",
+			invoke: func(g *Game) {
+				g.RevealCell(1, 2)
+				g.RevealCell(1, 0)
+			},
+			wantEvents: []Event{
+				CellRevealed{Cells: []RevealedCell{
+					{Row: 1, Column: 2, Content: ZeroCellValue},
+					{Row: 0, Column: 1, Content: OneCellValue},
+					{Row: 0, Column: 2, Content: ZeroCellValue},
+					{Row: 1, Column: 1, Content: TwoCellValue},
+					{Row: 2, Column: 1, Content: OneCellValue},
+					{Row: 2, Column: 2, Content: ZeroCellValue},
+				}},
+				CellRevealed{Cells: []RevealedCell{{Row: 1, Column: 0, Content: TwoCellValue}}},
+				GameWon{},
+			},
+		},
+		{
+			name: "reveal black hole",
+			invoke: func(g *Game) {
+				g.RevealCell(2, 0)
+			},
+			wantEvents: []Event{
+				GameLost{Row: 2, Column: 0},
+			},
+		},
+		{
+			name: "toggle flag",
+			invoke: func(g *Game) {
+				g.ToggleFlag(0, 0)
+				g.ToggleFlag(0, 0)
+			},
+			wantEvents: []Event{
+				CellFlagged{Row: 0, Column: 0, Flagged: true},
+				CellFlagged{Row: 0, Column: 0, Flagged: false},
+			},
+		},
+		{
+			name: "chord cell with wrong flag",
+			invoke: func(g *Game) {
+				g.RevealCell(1, 1)
+				g.ToggleFlag(1, 0)
+				g.ToggleFlag(0, 0)
+				g.ChordCell(1, 1)
+			},
+			wantEvents: []Event{
+				CellRevealed{Cells: []RevealedCell{{Row: 1, Column: 1, Content: TwoCellValue}}},
+				CellFlagged{Row: 1, Column: 0, Flagged: true},
+				CellFlagged{Row: 0, Column: 0, Flagged: true},
+				CellRevealed{Cells: []RevealedCell{
+					{Row: 0, Column: 1, Content: OneCellValue},
+					{Row: 0, Column: 2, Content: ZeroCellValue},
+					{Row: 1, Column: 2, Content: ZeroCellValue},
+					{Row: 2, Column: 1, Content: OneCellValue},
+					{Row: 2, Column: 2, Content: ZeroCellValue},
+				}},
+				GameLost{Row: 2, Column: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGame()
+			var got []Event
+			g.Subscribe(func(e Event) {
+				got = append(got, e)
+			})
+
+			tt.invoke(g)
+
+			assert.Equal(t, tt.wantEvents, got)
+		})
+	}
+}
+
+func TestGame_Subscribe_unsubscribe(t *testing.T) {
+	g := &Game{
+		board: [][]Cell{{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: HiddenState}}},
+	}
+
+	var first, second int
+	unsubscribeFirst := g.Subscribe(func(e Event) {
+		first++
+	})
+	g.Subscribe(func(e Event) {
+		second++
+	})
+
+	g.ToggleFlag(0, 0)
+	unsubscribeFirst()
+	unsubscribeFirst()
+	g.ToggleFlag(0, 0)
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+}
@@ -0,0 +1,106 @@
+package formats
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/require"
+)
+
+// maxFuzzReplay limits the size of fuzzed replays, as boards grow with the
+// product of their lines and the longest line.
+const maxFuzzReplay = 1 << 12
+
+func FuzzReadMBF(f *testing.F) {
+	data, err := os.ReadFile("testdata/beginner.mbf")
+	require.NoError(f, err)
+	f.Add(data)
+	f.Add([]byte{2, 2, 0, 1, 1, 1})
+	f.Add([]byte{0, 0, 0, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := ReadMBF(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		if err := g.Validate(); err != nil {
+			t.Fatalf("read inconsistent board: %v", err)
+		}
+
+		written := &bytes.Buffer{}
+		if err := WriteMBF(written, g); err != nil {
+			t.Fatalf("read board is not written: %v", err)
+		}
+		read, err := ReadMBF(written)
+		if err != nil {
+			t.Fatalf("written board is not read: %v", err)
+		}
+		if game.FormatBoard(read) != game.FormatBoard(g) {
+			t.Fatalf("board\n%s\nis read as\n%s", game.FormatBoard(g), game.FormatBoard(read))
+		}
+	})
+}
+
+func FuzzReadRAWVF(f *testing.F) {
+	for _, name := range []string{"testdata/beginner.rawvf", "testdata/clicks.rawvf"} {
+		data, err := os.ReadFile(name)
+		require.NoError(f, err)
+		f.Add(string(data))
+	}
+	f.Add("Width: 2\nHeight: 1\nMines: 1\nBoard:\n*0\nEvents:\n0.50 lr 2 1 (24 8)\n")
+	f.Add("Board:\n")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		if len(text) > maxFuzzReplay {
+			return
+		}
+
+		replay, err := ReadRAWVF(strings.NewReader(text))
+		if err != nil {
+			return
+		}
+
+		g, err := replay.NewGame()
+		if err != nil {
+			t.Fatalf("read replay has invalid board: %v", err)
+		}
+		if err := g.Validate(); err != nil {
+			t.Fatalf("read replay has inconsistent board: %v", err)
+		}
+		for _, m := range replay.Moves {
+			g.Play(m.Move)
+		}
+
+		// times are written in milliseconds, so written replays are read
+		// the same from the first write on
+		first := writeReadRAWVF(t, replay)
+		require.Equal(t, replay.Rows, first.Rows)
+		require.Equal(t, replay.Columns, first.Columns)
+		require.Equal(t, replay.BlackHoles, first.BlackHoles)
+		require.Equal(t, len(replay.Moves), len(first.Moves))
+		for i, m := range replay.Moves {
+			require.Equal(t, m.Move, first.Moves[i].Move)
+		}
+		require.Equal(t, first, writeReadRAWVF(t, first))
+	})
+}
+
+// writeReadRAWVF writes the replay and reads it back.
+func writeReadRAWVF(t *testing.T, replay Replay) Replay {
+	t.Helper()
+
+	written := &bytes.Buffer{}
+	if err := WriteRAWVF(written, replay); err != nil {
+		t.Fatalf("replay is not written: %v", err)
+	}
+	read, err := ReadRAWVF(written)
+	if err != nil {
+		t.Fatalf("written replay\n%s\nis not read: %v", written.String(), err)
+	}
+
+	return read
+}
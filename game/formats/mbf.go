@@ -0,0 +1,78 @@
+// Package formats reads and writes boards and replays of formats used by
+// Minesweeper tools, mapping them to games and moves of the game package.
+package formats
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// ReadMBF reads the game of the board in the Minesweeper Board Format. The
+// format holds the width and height of the board in a byte each, the number of
+// mines in two big-endian bytes, and the column and row of every mine in a byte
+// each.
+func ReadMBF(r io.Reader) (*game.Game, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("mbf header: %w", err)
+	}
+
+	columns, rows := int(header[0]), int(header[1])
+	mines := make([]byte, 2*int(binary.BigEndian.Uint16(header[2:])))
+	if _, err := io.ReadFull(r, mines); err != nil {
+		return nil, fmt.Errorf("mbf mines: %w", err)
+	}
+
+	blackHoles := make([]game.Position, 0, len(mines)/2)
+	for i := 0; i < len(mines); i += 2 {
+		blackHoles = append(blackHoles, game.Position{Row: int(mines[i+1]), Column: int(mines[i])})
+	}
+
+	g, err := game.NewGameFromLayout(rows, columns, blackHoles)
+	if err != nil {
+		return nil, fmt.Errorf("mbf: %w", err)
+	}
+
+	return g, nil
+}
+
+// WriteMBF writes the board of the game in the Minesweeper Board Format.
+// Boards over 255 rows or columns, with over 65535 black holes or with absent
+// cells can not be written.
+func WriteMBF(w io.Writer, g *game.Game) error {
+	state := g.GetState()
+
+	rows, columns := len(state), 0
+	if rows > 0 {
+		columns = len(state[0])
+	}
+	if rows > 255 || columns > 255 {
+		return fmt.Errorf("mbf: %dx%d board does not fit in the format", rows, columns)
+	}
+
+	var mines []byte
+	for i, row := range state {
+		for j, cell := range row {
+			if cell.State == game.AbsentState {
+				return errors.New("mbf: irregular boards do not fit in the format")
+			}
+			if cell.Content == game.BlackHoleCellValue {
+				mines = append(mines, byte(j), byte(i))
+			}
+		}
+	}
+	if len(mines)/2 > 0xffff {
+		return fmt.Errorf("mbf: %d black holes do not fit in the format", len(mines)/2)
+	}
+
+	header := []byte{byte(columns), byte(rows), 0, 0}
+	binary.BigEndian.PutUint16(header[2:], uint16(len(mines)/2))
+
+	_, err := w.Write(append(header, mines...))
+
+	return err
+}
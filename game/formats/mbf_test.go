@@ -0,0 +1,57 @@
+package formats
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMBF(t *testing.T) {
+	data, err := os.ReadFile("testdata/beginner.mbf")
+	require.NoError(t, err)
+
+	g, err := ReadMBF(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, ""+
+		".*....*.\n"+
+		"...*....\n"+
+		"*.......\n"+
+		".......*\n"+
+		"....*...\n"+
+		"..*.....\n"+
+		"......*.\n"+
+		"*....*..\n", game.FormatBoard(g))
+
+	written := bytes.Buffer{}
+	require.NoError(t, WriteMBF(&written, g))
+	assert.Equal(t, data, written.Bytes())
+}
+
+func TestReadMBF_invalid(t *testing.T) {
+	tests := map[string][]byte{
+		"short header":   {8, 8, 0},
+		"missing mines":  {8, 8, 0, 2, 0, 0},
+		"mine off board": {2, 2, 0, 1, 2, 0},
+		"duplicate mine": {2, 2, 0, 2, 1, 1, 1, 1},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadMBF(bytes.NewReader(data))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteMBF_unsupported(t *testing.T) {
+	assert.Error(t, WriteMBF(&bytes.Buffer{}, game.NewGame(256, 1)))
+
+	g, err := game.ParseBoard(bytes.NewBufferString("*.\n.\n"))
+	require.NoError(t, err)
+	assert.Error(t, WriteMBF(&bytes.Buffer{}, g))
+}
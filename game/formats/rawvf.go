@@ -0,0 +1,314 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// rawvfVersion is written by WriteRAWVF unless the replay has its own.
+const rawvfVersion = "Rev5"
+
+// rawvfCellPixels is the size of cells in pixels written to replay events.
+const rawvfCellPixels = 16
+
+// Replay is a game recorded in the RAWVF replay format: the board and moves
+// made by the player.
+type Replay struct {
+	// Properties are header fields of the replay other than the board
+	// dimensions and the number of mines, for example Program or Player.
+	Properties []Property
+	Rows       int
+	Columns    int
+	BlackHoles []game.Position
+	Moves      []ReplayMove
+}
+
+// Property is a header field of the replay.
+type Property struct {
+	Key   string
+	Value string
+}
+
+// ReplayMove is the move made at Time since the start of the game.
+type ReplayMove struct {
+	Time time.Duration
+	Move game.Move
+}
+
+// NewReplay returns the replay of the moves made on the board of the game.
+func NewReplay(g *game.Game, moves []ReplayMove) Replay {
+	state := g.GetState()
+
+	r := Replay{Rows: len(state), Moves: moves}
+	if len(state) > 0 {
+		r.Columns = len(state[0])
+	}
+	for i, row := range state {
+		for j, cell := range row {
+			if cell.Content == game.BlackHoleCellValue {
+				r.BlackHoles = append(r.BlackHoles, game.Position{Row: i, Column: j})
+			}
+		}
+	}
+
+	return r
+}
+
+// NewGame returns the new game of the replay board. Moves of the replay are
+// not made.
+func (r Replay) NewGame() (*game.Game, error) {
+	return game.NewGameFromLayout(r.Rows, r.Columns, r.BlackHoles)
+}
+
+// ReadRAWVF reads the replay in the RAWVF text format. Releases of the left
+// button reveal cells, presses of the right button toggle flags and releases
+// of the middle button chord cells. Other events, such as mouse moves, are
+// skipped.
+//
+// Players click cells which can not change, for example numbers and flags, so
+// moves are made on the board while reading, and moves changing nothing are
+// dropped. Every move of the read replay can be played on its new game.
+func ReadRAWVF(r io.Reader) (Replay, error) {
+	const (
+		headerSection = iota
+		boardSection
+		eventsSection
+	)
+
+	var replay Replay
+	mines := -1
+	section := headerSection
+	boardRows := 0
+
+	lineNumber := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+		case line == "Board:":
+			if replay.Rows < 1 || replay.Columns < 1 {
+				return Replay{}, fmt.Errorf("rawvf line %d: board without width and height", lineNumber)
+			}
+			section = boardSection
+		case line == "Events:":
+			if section != boardSection {
+				return Replay{}, fmt.Errorf("rawvf line %d: events before board", lineNumber)
+			}
+			if boardRows != replay.Rows {
+				return Replay{}, fmt.Errorf("rawvf line %d: %d board rows, want %d", lineNumber, boardRows, replay.Rows)
+			}
+			section = eventsSection
+		case section == boardSection:
+			if boardRows == replay.Rows {
+				return Replay{}, fmt.Errorf("rawvf line %d: board row out of height", lineNumber)
+			}
+			if len(line) != replay.Columns {
+				return Replay{}, fmt.Errorf("rawvf line %d: board row of %d cells, want %d", lineNumber, len(line), replay.Columns)
+			}
+
+			for j, c := range line {
+				switch c {
+				case '*':
+					replay.BlackHoles = append(replay.BlackHoles, game.Position{Row: boardRows, Column: j})
+				case '0', '.':
+				default:
+					return Replay{}, fmt.Errorf("rawvf line %d: unexpected board cell %q", lineNumber, c)
+				}
+			}
+			boardRows++
+		case section == eventsSection:
+			move, ok, err := parseRAWVFEvent(line)
+			if err != nil {
+				return Replay{}, fmt.Errorf("rawvf line %d: %w", lineNumber, err)
+			}
+			if ok {
+				replay.Moves = append(replay.Moves, move)
+			}
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return Replay{}, fmt.Errorf("rawvf line %d: header field %q without colon", lineNumber, line)
+			}
+			value = strings.TrimSpace(value)
+
+			var err error
+			switch key {
+			case "Width":
+				replay.Columns, err = strconv.Atoi(value)
+			case "Height":
+				replay.Rows, err = strconv.Atoi(value)
+			case "Mines":
+				mines, err = strconv.Atoi(value)
+			default:
+				replay.Properties = append(replay.Properties, Property{Key: key, Value: value})
+			}
+			if err != nil {
+				return Replay{}, fmt.Errorf("rawvf line %d: %s: %w", lineNumber, key, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Replay{}, err
+	}
+
+	if section == headerSection || boardRows != replay.Rows {
+		return Replay{}, fmt.Errorf("rawvf: incomplete board")
+	}
+	if mines >= 0 && mines != len(replay.BlackHoles) {
+		return Replay{}, fmt.Errorf("rawvf: %d mines declared, %d on the board", mines, len(replay.BlackHoles))
+	}
+
+	g, err := replay.NewGame()
+	if err != nil {
+		return Replay{}, fmt.Errorf("rawvf: %w", err)
+	}
+	replay.Moves = playMoves(g, replay.Moves)
+
+	return replay, nil
+}
+
+// playMoves makes the moves on the game and returns the moves which changed
+// it. Moves which would panic, such as revealing a flagged cell or any move
+// after the game is completed, are not made.
+func playMoves(g *game.Game, moves []ReplayMove) []ReplayMove {
+	var played []ReplayMove
+	for _, m := range moves {
+		if g.Completed() {
+			break
+		}
+
+		cell, ok := g.PlayerCellAt(m.Move.Row, m.Move.Column)
+		if !ok {
+			continue
+		}
+		switch m.Move.Kind {
+		case game.RevealMove:
+			ok = cell.State == game.HiddenState
+		case game.FlagMove:
+			ok = cell.State != game.VisibleState
+		case game.ChordMove:
+			ok = cell.State == game.VisibleState
+		}
+		if !ok {
+			continue
+		}
+
+		version := g.Version()
+		g.Play(m.Move)
+		if changes, _ := g.ChangesSince(version); len(changes) > 0 || g.Completed() {
+			played = append(played, m)
+		}
+	}
+
+	return played
+}
+
+// parseRAWVFEvent parses the event line such as "1.25 lr 3 5 (40 72)", where
+// the column and row are counted from 1. If the event is not a move, then ok
+// is false.
+func parseRAWVFEvent(line string) (move ReplayMove, ok bool, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return ReplayMove{}, false, fmt.Errorf("event %q without time and kind", line)
+	}
+
+	var kind game.MoveKind
+	switch fields[1] {
+	case "lr":
+		kind = game.RevealMove
+	case "rc":
+		kind = game.FlagMove
+	case "mr":
+		kind = game.ChordMove
+	default:
+		return ReplayMove{}, false, nil
+	}
+
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return ReplayMove{}, false, fmt.Errorf("event time: %w", err)
+	}
+	if len(fields) < 4 {
+		return ReplayMove{}, false, fmt.Errorf("event %q without cell", line)
+	}
+	column, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return ReplayMove{}, false, fmt.Errorf("event column: %w", err)
+	}
+	row, err := strconv.Atoi(fields[3])
+	if err != nil {
+		return ReplayMove{}, false, fmt.Errorf("event row: %w", err)
+	}
+
+	return ReplayMove{
+		Time: time.Duration(seconds*1000+0.5) * time.Millisecond,
+		Move: game.Move{Kind: kind, Row: row - 1, Column: column - 1},
+	}, true, nil
+}
+
+// WriteRAWVF writes the replay in the RAWVF text format. Every move is written
+// as the press and the release of the button making it.
+func WriteRAWVF(w io.Writer, r Replay) error {
+	bw := bufio.NewWriter(w)
+
+	versioned := false
+	for _, p := range r.Properties {
+		versioned = versioned || p.Key == "RawVF_Version"
+	}
+	if !versioned {
+		fmt.Fprintf(bw, "RawVF_Version: %s\n", rawvfVersion)
+	}
+	for _, p := range r.Properties {
+		fmt.Fprintf(bw, "%s: %s\n", p.Key, p.Value)
+	}
+	fmt.Fprintf(bw, "Width: %d\nHeight: %d\nMines: %d\n", r.Columns, r.Rows, len(r.BlackHoles))
+
+	board := make([][]byte, r.Rows)
+	for i := range board {
+		board[i] = []byte(strings.Repeat("0", r.Columns))
+	}
+	for _, p := range r.BlackHoles {
+		if p.Row < 0 || p.Row >= r.Rows || p.Column < 0 || p.Column >= r.Columns {
+			return fmt.Errorf("rawvf: black hole at %d:%d: %w", p.Row, p.Column, game.ErrPositionOutOfRange)
+		}
+		board[p.Row][p.Column] = '*'
+	}
+	bw.WriteString("Board:\n")
+	for _, row := range board {
+		bw.Write(row)
+		bw.WriteByte('\n')
+	}
+
+	bw.WriteString("Events:\n")
+	for _, m := range r.Moves {
+		var press, release string
+		switch m.Move.Kind {
+		case game.RevealMove:
+			press, release = "lc", "lr"
+		case game.FlagMove:
+			press, release = "rc", "rr"
+		case game.ChordMove:
+			press, release = "mc", "mr"
+		default:
+			return fmt.Errorf("rawvf: unknown move kind %d", m.Move.Kind)
+		}
+
+		seconds := float64(m.Time.Milliseconds()) / 1000
+		column, row := m.Move.Column+1, m.Move.Row+1
+		x, y := m.Move.Column*rawvfCellPixels+rawvfCellPixels/2, m.Move.Row*rawvfCellPixels+rawvfCellPixels/2
+		for _, event := range []string{press, release} {
+			fmt.Fprintf(bw, "%.3f %s %d %d (%d %d)\n", seconds, event, column, row, x, y)
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,133 @@
+package formats
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/kalynv/proxx/game/gametest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRAWVF(t *testing.T) {
+	f, err := os.Open("testdata/beginner.rawvf")
+	require.NoError(t, err)
+	defer f.Close()
+
+	replay, err := ReadRAWVF(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, Replay{
+		Properties: []Property{
+			{Key: "RawVF_Version", Value: "Rev5"},
+			{Key: "Program", Value: "Minesweeper Arbiter"},
+			{Key: "Version", Value: "0.52.3"},
+			{Key: "Player", Value: "Anonymous"},
+			{Key: "Timestamp", Value: "1700000000"},
+			{Key: "Level", Value: "Custom"},
+			{Key: "Marks", Value: "Off"},
+		},
+		Rows:       3,
+		Columns:    3,
+		BlackHoles: []game.Position{{Row: 0, Column: 0}, {Row: 2, Column: 0}},
+		Moves: []ReplayMove{
+			{Time: 120 * time.Millisecond, Move: game.Move{Kind: game.RevealMove, Row: 1, Column: 1}},
+			{Time: 500 * time.Millisecond, Move: game.Move{Kind: game.FlagMove, Row: 0, Column: 0}},
+			{Time: 900 * time.Millisecond, Move: game.Move{Kind: game.FlagMove, Row: 2, Column: 0}},
+			{Time: 1375 * time.Millisecond, Move: game.Move{Kind: game.ChordMove, Row: 1, Column: 1}},
+		},
+	}, replay)
+
+	g, err := replay.NewGame()
+	require.NoError(t, err)
+	for _, m := range replay.Moves {
+		g.Play(m.Move)
+	}
+	assert.True(t, g.Won())
+}
+
+func TestReadRAWVF_idleClicks(t *testing.T) {
+	f, err := os.Open("testdata/clicks.rawvf")
+	require.NoError(t, err)
+	defer f.Close()
+
+	// clicks on a number, a flag, out of the board, a zero, a number with
+	// unmatched flags and after the game is won are dropped
+	replay, err := ReadRAWVF(f)
+	require.NoError(t, err)
+	assert.Equal(t, []ReplayMove{
+		{Time: 100 * time.Millisecond, Move: game.Move{Kind: game.RevealMove, Row: 0, Column: 2}},
+		{Time: 800 * time.Millisecond, Move: game.Move{Kind: game.FlagMove, Row: 0, Column: 0}},
+		{Time: 2375 * time.Millisecond, Move: game.Move{Kind: game.ChordMove, Row: 1, Column: 1}},
+	}, replay.Moves)
+
+	g, err := replay.NewGame()
+	require.NoError(t, err)
+	for _, m := range replay.Moves {
+		g.Play(m.Move)
+	}
+	assert.True(t, g.Won())
+}
+
+func TestWriteRAWVF(t *testing.T) {
+	f, err := os.Open("testdata/beginner.rawvf")
+	require.NoError(t, err)
+	defer f.Close()
+
+	replay, err := ReadRAWVF(f)
+	require.NoError(t, err)
+
+	written := bytes.Buffer{}
+	require.NoError(t, WriteRAWVF(&written, replay))
+	got, err := ReadRAWVF(&written)
+	require.NoError(t, err)
+
+	assert.Equal(t, replay, got)
+}
+
+func TestNewReplay(t *testing.T) {
+	g := gametest.NewGame(t, gametest.Lines("...", "..*"))
+	moves := []ReplayMove{{Time: time.Second, Move: game.Move{Kind: game.RevealMove, Row: 0, Column: 0}}}
+
+	written := bytes.Buffer{}
+	require.NoError(t, WriteRAWVF(&written, NewReplay(g, moves)))
+
+	assert.Equal(t, ""+
+		"RawVF_Version: Rev5\n"+
+		"Width: 3\n"+
+		"Height: 2\n"+
+		"Mines: 1\n"+
+		"Board:\n"+
+		"000\n"+
+		"00*\n"+
+		"Events:\n"+
+		"1.000 lc 1 1 (8 8)\n"+
+		"1.000 lr 1 1 (8 8)\n", written.String())
+}
+
+func TestReadRAWVF_invalid(t *testing.T) {
+	tests := map[string]string{
+		"no board":            "Width: 2\nHeight: 1\n",
+		"board without size":  "Board:\n00\n",
+		"short board":         "Width: 2\nHeight: 2\nBoard:\n00\nEvents:\n",
+		"events before board": "Events:\n",
+		"wide row":            "Width: 2\nHeight: 1\nBoard:\n000\n",
+		"unknown cell":        "Width: 2\nHeight: 1\nBoard:\n0?\n",
+		"mines mismatch":      "Width: 2\nHeight: 1\nMines: 2\nBoard:\n0*\n",
+		"bad width":           "Width: two\n",
+		"bad header":          "Width 2\n",
+		"bad event time":      "Width: 2\nHeight: 1\nBoard:\n00\nEvents:\nnow lr 1 1\n",
+		"event without cell":  "Width: 2\nHeight: 1\nBoard:\n00\nEvents:\n0.1 lr\n",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadRAWVF(strings.NewReader(text))
+
+			assert.Error(t, err)
+		})
+	}
+}
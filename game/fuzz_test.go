@@ -0,0 +1,238 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// documentedPanics are messages of panics documented for moves.
+var documentedPanics = []string{
+	"game over",
+	"game paused",
+	"game is not in progress",
+	"game is not paused",
+	"non-existing cell addressed",
+	"cell already visible",
+	"cell flagged",
+	"cell is not visible",
+}
+
+// fuzzTopologies are topologies fuzzed games are played on. Games are also
+// played on grids of layers of the board, which depend on the board size.
+var fuzzTopologies = []Topology{Square{}, Torus{}, Hex{}, KnightMask()}
+
+// fuzzShape returns the shape and topology of the fuzzed game. The topology
+// picks one of fuzzTopologies or a grid of up to three layers, and bits of
+// absent mark cells missing from the board.
+func fuzzShape(rows, columns, topology uint8, absent uint64) (Shape, Topology) {
+	rows, columns = rows%16+1, columns%16+1
+
+	var t Topology
+	layers := 1
+	if k := int(topology) % (len(fuzzTopologies) + 1); k < len(fuzzTopologies) {
+		t = fuzzTopologies[k]
+	} else {
+		layers = int(topology)/(len(fuzzTopologies)+1)%3 + 1
+		t = NewGrid(layers, int(rows), int(columns))
+	}
+
+	shape := NewShape(layers*int(rows), int(columns))
+	for k := 0; k < layers*int(rows)*int(columns); k++ {
+		if absent>>(k%64)&1 == 1 {
+			shape = shape.Without(k/int(columns), k%int(columns))
+		}
+	}
+
+	return shape, t
+}
+
+// visibleCells returns the number of visible cells of the game.
+func visibleCells(g *Game) int {
+	n := 0
+	for _, row := range g.board {
+		for _, cell := range row {
+			if cell.State == VisibleState {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// tryMove makes the move and returns the message of its panic. Games are not
+// expected to change when moves panic.
+func tryMove(t *testing.T, g *Game, move func()) (panicked string) {
+	before := FormatBoard(g)
+	version := g.Version()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		panicked = fmt.Sprint(r)
+		documented := false
+		for _, message := range documentedPanics {
+			documented = documented || panicked == message
+		}
+		if !documented {
+			t.Fatalf("undocumented panic %q", panicked)
+		}
+		if after := FormatBoard(g); after != before || g.Version() != version {
+			t.Fatalf("panic %q changed the game from\n%s\nto\n%s", panicked, before, after)
+		}
+	}()
+
+	move()
+
+	return ""
+}
+
+// maxFuzzMoves limits moves of fuzzed games.
+const maxFuzzMoves = 256
+
+// maxFuzzText limits the size of fuzzed boards, as boards grow with the
+// product of lines and the longest line.
+const maxFuzzText = 1 << 10
+
+// FuzzGame plays moves encoded by bytes on seeded games of shapes and
+// topologies made by fuzzShape. Every move takes two bytes: the kind of the
+// move and the index of the cell.
+func FuzzGame(f *testing.F) {
+	f.Add(uint64(1), uint8(2), uint8(2), uint8(2), uint8(0), uint64(0), []byte{0, 4, 1, 0, 2, 0})
+	f.Add(uint64(42), uint8(7), uint8(7), uint8(10), uint8(1), uint64(0), []byte{0, 0, 0, 63, 3, 9, 4, 1, 3, 9, 0, 27})
+	f.Add(uint64(7), uint8(4), uint8(8), uint8(0), uint8(2), uint64(1<<13), []byte{0, 22})
+	f.Add(uint64(3), uint8(5), uint8(5), uint8(35), uint8(3), uint64(0x8421), []byte{2, 1, 1, 1, 5, 0, 0, 1})
+	f.Add(uint64(5), uint8(2), uint8(2), uint8(4), uint8(9), uint64(0), []byte{0, 13, 1, 0, 0, 26})
+	f.Add(uint64(9), uint8(4), uint8(4), uint8(3), uint8(4), uint64(0x1001), []byte{0, 5, 0, 6, 2, 10})
+
+	f.Fuzz(func(t *testing.T, seed uint64, rows, columns, blackHoles, topology uint8, absent uint64, moves []byte) {
+		// games are validated after every move, so long games are cut
+		if len(moves) > maxFuzzMoves*2 {
+			moves = moves[:maxFuzzMoves*2]
+		}
+		shape, shapeTopology := fuzzShape(rows, columns, topology, absent)
+		width := shape.columns
+		cells := shape.Cells()
+		g := NewShapedGame(shape, int(blackHoles)%(cells+1), WithTopology(shapeTopology), WithSeed(seed))
+		safe := cells - int(blackHoles)%(cells+1)
+
+		visible := 0
+		for i := 0; i+1 < len(moves); i += 2 {
+			completed := g.Completed()
+			row, column := int(moves[i+1])/width, int(moves[i+1])%width
+
+			var panicked string
+			switch moves[i] % 6 {
+			case 0, 1, 2:
+				panicked = tryMove(t, g, func() { g.Play(Move{Kind: MoveKind(moves[i] % 3), Row: row, Column: column}) })
+			case 3:
+				panicked = tryMove(t, g, g.Pause)
+			case 4:
+				panicked = tryMove(t, g, g.Resume)
+			case 5:
+				// resigning ends the game, so it is left for the last move
+				if i+3 >= len(moves) {
+					panicked = tryMove(t, g, g.Resign)
+				}
+			}
+
+			if completed && panicked == "" && moves[i]%6 < 3 {
+				t.Fatalf("move %d made on completed game", i/2)
+			}
+			if completed && !g.Completed() {
+				t.Fatalf("move %d restarted completed game", i/2)
+			}
+			if err := g.Validate(); err != nil {
+				t.Fatalf("move %d made inconsistent game:\n%s\n%v", i/2, FormatBoard(g), err)
+			}
+			if v := visibleCells(g); v < visible {
+				t.Fatalf("move %d hid %d cells", i/2, visible-v)
+			} else {
+				visible = v
+			}
+
+			_, _, failed := g.FailedAt()
+			if failed != (g.Status() == Lost) {
+				t.Fatalf("status %s of game failed %t", g.Status(), failed)
+			}
+			if g.Won() != (g.Status() == Won) || g.Won() != (!failed && g.status != Resigned && visible == safe) {
+				t.Fatalf("status %s of game with %d of %d cells visible", g.Status(), visible, cells)
+			}
+		}
+	})
+}
+
+// FuzzParseBoard checks that parsed boards are consistent and survive
+// formatting.
+func FuzzParseBoard(f *testing.F) {
+	f.Add("*1..*\n.....\n")
+	f.Add("@F.\n221\n000\n")
+	f.Add("*3X\n@3F\n 21\n")
+	f.Add(" .. \n....\n .. \n")
+	f.Add("+\n")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		if len(text) > maxFuzzText {
+			return
+		}
+
+		g, err := ParseBoard(strings.NewReader(text))
+		if err != nil {
+			return
+		}
+
+		if err := g.Validate(); err != nil {
+			t.Fatalf("parsed inconsistent board: %v", err)
+		}
+
+		formatted := FormatBoard(g)
+		parsed, err := ParseBoard(strings.NewReader(formatted))
+		if err != nil {
+			t.Fatalf("formatted board\n%s\nis not parsed: %v", formatted, err)
+		}
+		if again := FormatBoard(parsed); again != formatted {
+			t.Fatalf("board\n%s\nis formatted as\n%s", formatted, again)
+		}
+	})
+}
+
+// FuzzNewGameFromCode checks that decoded boards are encoded to codes of the
+// same board.
+func FuzzNewGameFromCode(f *testing.F) {
+	for _, g := range []*Game{NewGame(3, 2, WithSeed(1)), NewGame(8, 10, WithTopology(Torus{}), WithSeed(2)), NewGame(5, 0, WithTopology(Hex{}))} {
+		code, err := ShareCode(g)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(code)
+	}
+	f.Add("")
+	f.Add("AQA")
+
+	f.Fuzz(func(t *testing.T, code string) {
+		g, err := NewGameFromCode(code)
+		if err != nil {
+			return
+		}
+
+		if err := g.Validate(); err != nil {
+			t.Fatalf("decoded inconsistent board: %v", err)
+		}
+
+		shared, err := ShareCode(g)
+		if err != nil {
+			t.Fatalf("decoded board is not encoded: %v", err)
+		}
+		decoded, err := NewGameFromCode(shared)
+		if err != nil {
+			t.Fatalf("code %q is not decoded: %v", shared, err)
+		}
+		if FormatBoard(decoded) != FormatBoard(g) || decoded.Topology() != g.Topology() {
+			t.Fatalf("code %q makes board\n%s\ninstead of\n%s", shared, FormatBoard(decoded), FormatBoard(g))
+		}
+	})
+}
@@ -2,7 +2,7 @@ package game
 
 import (
 	"fmt"
-	"math/rand"
+	"time"
 )
 
 type Cell struct {
@@ -10,6 +10,9 @@ type Cell struct {
 	State   CellState
 }
 
+// CellValue is the number of black holes neighbouring the cell or
+// BlackHoleCellValue. Cells may show numbers above EightCellValue on boards
+// with Mask topology.
 type CellValue int
 
 const ZeroCellValue CellValue = 0
@@ -28,6 +31,10 @@ type CellState int
 const (
 	HiddenState CellState = iota
 	VisibleState
+	FlaggedState
+	// AbsentState marks cells missing from irregular boards. Absent cells can
+	// not be addressed by moves.
+	AbsentState
 )
 
 type cellAddress struct {
@@ -35,27 +42,142 @@ type cellAddress struct {
 	column int
 }
 
-func NewGame(boardSize int, blackHolesNumber int) *Game {
+// NewGame returns the game on the square board of the size with black holes
+// placed randomly.
+// If the topology is a Grid not stored by the board, then NewGame panics.
+func NewGame(boardSize int, blackHolesNumber int, options ...Option) *Game {
+	o := newOptions(options)
+	if err := o.checkBoard(boardSize, boardSize); err != nil {
+		panic(err.Error())
+	}
+
 	board := createGameState(boardSize, boardSize, Cell{Content: ZeroCellValue, State: HiddenState})
-	blackHoleAddresses := generateBlackHoleAddresses(boardSize, boardSize, blackHolesNumber)
+	blackHoleAddresses := generateBlackHoleAddresses(o.rand(), boardSize, boardSize, blackHolesNumber)
+	replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, o.topology)
+
+	return o.newGame(board)
+}
+
+// NewGridGame returns the game on the N-dimensional grid. Cells are addressed
+// by the row and column returned by Grid.Address. The grid is the topology of
+// the game, so WithTopology options are ignored.
+// If the grid is not made by NewGrid, then NewGridGame panics.
+func NewGridGame(grid Grid, blackHolesNumber int, options ...Option) *Game {
+	if len(grid.dims) == 0 {
+		panic("grid must have dimensions")
+	}
+
+	o := newOptions(options)
+	o.topology = grid
+
+	rows, columns := grid.size()
+
+	board := createGameState(rows, columns, Cell{Content: ZeroCellValue, State: HiddenState})
+	blackHoleAddresses := generateBlackHoleAddresses(o.rand(), rows, columns, blackHolesNumber)
 	replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
-	updateNaboringBlackHolesCellValues(board)
+	updateNaboringBlackHolesCellValues(board, grid)
+
+	return o.newGame(board)
+}
+
+// Option configures the game created by NewGame.
+type Option func(o *options)
+
+type options struct {
+	topology Topology
+	seed     *uint64
+	clock    func() time.Time
+}
+
+func newOptions(opts []Option) options {
+	o := options{topology: Square{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithTopology makes the game use the topology. Square topology is used by
+// default.
+func WithTopology(t Topology) Option {
+	return func(o *options) {
+		o.topology = t
+	}
+}
+
+// WithSeed makes the game place black holes by the built-in generator seeded
+// with the seed, so the same seed and settings always make the same board.
+// Black holes are placed randomly by default.
+func WithSeed(seed uint64) Option {
+	return func(o *options) {
+		o.seed = &seed
+	}
+}
+
+// checkBoard returns an error wrapping ErrTopologyMismatch if the board of rows
+// and columns does not store all cells of the Grid topology. Neighbours of
+// grids are found by coordinates, so boards of other sizes are meaningless.
+func (o options) checkBoard(rows, columns int) error {
+	grid, ok := o.topology.(Grid)
+	if !ok {
+		return nil
+	}
+
+	if len(grid.dims) == 0 {
+		return fmt.Errorf("grid without dimensions: %w", ErrTopologyMismatch)
+	}
+	if gridRows, gridColumns := grid.size(); rows != gridRows || columns != gridColumns {
+		return fmt.Errorf("board of %d rows and %d columns, grid %v of %d rows and %d columns: %w",
+			rows, columns, grid.dims, gridRows, gridColumns, ErrTopologyMismatch)
+	}
+
+	return nil
+}
+
+// newGame returns the game of the board configured by the options.
+func (o options) newGame(board [][]Cell) *Game {
+	return &Game{failAt: nil, board: board, topology: o.topology, seed: o.seed, clock: o.clock}
+}
+
+// rand returns the generator placing black holes.
+func (o options) rand() intner {
+	if o.seed == nil {
+		return globalRand{}
+	}
 
-	return &Game{failAt: nil, board: board}
+	return newPCG32(*o.seed, 0)
 }
 
 // Game is a contrainer for a game state and implements methods to update state
 // according game rules.
 type Game struct {
-	failAt *cellAddress
-	board  [][]Cell
+	failAt      *cellAddress
+	board       [][]Cell
+	topology    Topology
+	subscribers []*subscriber
+	version     uint64
+	changes     []changeRecord
+	seed        *uint64
+	status      Status
+	clock       func() time.Time
+	elapsed     time.Duration
+	resumedAt   time.Time
 }
 
 // GetState clones the current Game state
 func (g *Game) GetState() [][]Cell {
+	total := 0
+	for _, boardRow := range g.board {
+		total += len(boardRow)
+	}
+
+	cells := make([]Cell, total)
 	rows := make([][]Cell, len(g.board))
 	for i, boardRow := range g.board {
-		row := make([]Cell, len(boardRow))
+		row := cells[:len(boardRow):len(boardRow)]
+		cells = cells[len(boardRow):]
 		copy(row, boardRow)
 		rows[i] = row
 	}
@@ -63,6 +185,73 @@ func (g *Game) GetState() [][]Cell {
 	return rows
 }
 
+// Topology returns the topology of the game board.
+func (g *Game) Topology() Topology {
+	if g.topology == nil {
+		return Square{}
+	}
+
+	return g.topology
+}
+
+// Seed returns the seed the board was made of. If the board is not seeded,
+// then ok is false.
+func (g *Game) Seed() (seed uint64, ok bool) {
+	if g.seed == nil {
+		return 0, false
+	}
+
+	return *g.seed, true
+}
+
+// Restart returns the new game on the same board with all cells hidden. The
+// topology, seed and clock of the game are kept, subscribers are not.
+func (g *Game) Restart() *Game {
+	board := g.GetState()
+	for i := range board {
+		for j := range board[i] {
+			if board[i][j].State != AbsentState {
+				board[i][j].State = HiddenState
+			}
+		}
+	}
+
+	return &Game{failAt: nil, board: board, topology: g.topology, seed: g.seed, clock: g.clock}
+}
+
+// Clone returns the independent copy of the game, so moves may be tried on it
+// without affecting the game. The board, the failed cell, the version and
+// history of changes, the seed, the status and the timer are copied.
+// Subscribers are not, the clone emits events to its own subscribers only.
+// Black holes are placed once the game is made, so no generator state is kept
+// past the seed.
+func (g *Game) Clone() *Game {
+	clone := &Game{
+		board:     g.GetState(),
+		topology:  g.topology,
+		version:   g.version,
+		status:    g.status,
+		clock:     g.clock,
+		elapsed:   g.elapsed,
+		resumedAt: g.resumedAt,
+	}
+
+	if g.failAt != nil {
+		failAt := *g.failAt
+		clone.failAt = &failAt
+	}
+	if g.seed != nil {
+		seed := *g.seed
+		clone.seed = &seed
+	}
+	if g.changes != nil {
+		clone.changes = make([]changeRecord, len(g.changes))
+		copy(clone.changes, g.changes)
+	}
+
+	return clone
+}
+
 // Lost returns true if the game is lost. Otherwise false is returned.
 func (g *Game) Lost() bool {
 	return g.failAt != nil
@@ -70,7 +259,7 @@ func (g *Game) Lost() bool {
 
 // Won returns true if the game is won. Otherwise false is returned.
 func (g *Game) Won() bool {
-	if g.Lost() {
+	if g.Lost() || g.status == Resigned {
 		return false
 	}
 
@@ -78,7 +267,7 @@ func (g *Game) Won() bool {
 }
 
 // won return true if all cells are revealed except of cells with
-// BlackHoleCellValue in State field.
+// BlackHoleCellValue in State field. Absent cells are not counted.
 func (g *Game) won() bool {
 	totalCells := 0
 	visibleCells := 0
@@ -86,6 +275,9 @@ func (g *Game) won() bool {
 
 	for _, row := range g.board {
 		for _, cell := range row {
+			if cell.State == AbsentState {
+				continue
+			}
 			totalCells++
 			if cell.State == VisibleState {
 				visibleCells++
@@ -99,15 +291,28 @@ func (g *Game) won() bool {
 	return (totalCells - visibleCells) == blackHoleCells
 }
 
-// Completed returns true if the game is over.
+// FailedAt returns the row and column of the black hole revealed to lose the
+// game. If the game is not lost, then failed is false.
+func (g *Game) FailedAt() (row, column int, failed bool) {
+	if g.failAt == nil {
+		return 0, 0, false
+	}
+
+	return g.failAt.row, g.failAt.column, true
+}
+
+// Completed returns true if the game is won, lost or resigned.
 func (g *Game) Completed() bool {
-	return g.Lost() || g.Won()
+	return g.Lost() || g.status == Resigned || g.Won()
 }
 
 // RevealCell update the cell State field to be `VisibleState`.
 // If the game is completed, then RevealCell will panic.
+// If the game is paused, then RevealCell panics, the game must be resumed first.
 // If supplied i row and j column can not address a cell in the game, then
 // RevealCall panics.
+// Flagged cells can not be revealed, RevealCell panics for them. Flagged cells
+// are left untouched while opening contiguous space of empty cells.
 func (g *Game) RevealCell(i, j int) {
 	if g.Completed() {
 		panic("game over")
@@ -123,8 +328,16 @@ func (g *Game) RevealCell(i, j int) {
 		panic("non-existing cell addressed")
 	}
 
+	if cell.State == FlaggedState {
+		panic("cell flagged")
+	}
+
 	if cell.Content == BlackHoleCellValue {
+		g.startMove()
 		g.failAt = &address
+		g.finishMove()
+		g.record()
+		g.emit(g.revealEvents(nil)...)
 
 		return
 	}
@@ -133,96 +346,168 @@ func (g *Game) RevealCell(i, j int) {
 		panic("cell already visible")
 	}
 
+	g.startMove()
 	cell.State = VisibleState
+	revealed := []cellAddress{address}
 
 	if cell.Content == ZeroCellValue {
-		g.revealSurrounding(address)
+		revealed = g.revealSurrounding(address, revealed)
 	}
+
+	g.finishMove()
+	g.record(revealed...)
+	g.emit(g.revealEvents(revealed)...)
 }
 
-func getCell(board [][]Cell, a cellAddress) *Cell {
-	if a.row < 0 || a.row >= len(board) {
-		return nil
+// ChordCell reveals all hidden cells surrounding the visible cell if the
+// number of flagged surrounding cells equals the cell value. Otherwise
+// ChordCell does nothing.
+// If the game is completed, then ChordCell will panic.
+// If the game is paused, then ChordCell panics, the game must be resumed first.
+// If supplied i row and j column can not address a visible cell in the game,
+// then ChordCell panics.
+func (g *Game) ChordCell(i, j int) {
+	if g.Completed() {
+		panic("game over")
 	}
-	if a.column < 0 || a.column >= len(board[a.row]) {
-		return nil
+
+	address := cellAddress{
+		row:    i,
+		column: j,
 	}
 
-	return &board[a.row][a.column]
-}
+	cell := getCell(g.board, address)
+	if cell == nil {
+		panic("non-existing cell addressed")
+	}
 
-func (g *Game) revealSurrounding(ca cellAddress) {
-	for _, currentAddress := range surroundingAddresses(ca) {
-		cell := getCell(g.board, currentAddress)
+	if cell.State != VisibleState {
+		panic("cell is not visible")
+	}
 
-		if cell == nil {
-			continue
+	g.startMove()
+
+	flagged := 0
+	neighbours := g.Topology().neighbours(g.board, address)
+	for _, currentAddress := range neighbours {
+		surrounding := getCell(g.board, currentAddress)
+		if surrounding.State == FlaggedState {
+			flagged++
 		}
+	}
 
-		if cell.State == VisibleState {
+	if CellValue(flagged) != cell.Content {
+		return
+	}
+
+	var revealed []cellAddress
+	for _, currentAddress := range neighbours {
+		surrounding := getCell(g.board, currentAddress)
+		if surrounding.State != HiddenState {
 			continue
 		}
 
-		if cell.Content == BlackHoleCellValue {
-			panic("unexpected condition: revealing naboring black hole")
+		if surrounding.Content == BlackHoleCellValue {
+			failAt := currentAddress
+			g.failAt = &failAt
+
+			break
 		}
 
-		cell.State = VisibleState
+		surrounding.State = VisibleState
+		revealed = append(revealed, currentAddress)
 
-		if cell.Content == ZeroCellValue {
-			g.revealSurrounding(currentAddress)
+		if surrounding.Content == ZeroCellValue {
+			revealed = g.revealSurrounding(currentAddress, revealed)
 		}
 	}
+
+	g.finishMove()
+	g.record(revealed...)
+	g.emit(g.revealEvents(revealed)...)
 }
 
-func surroundingAddresses(a cellAddress) []cellAddress {
-	if a.row < 0 {
-		panic("row must not be negative")
+// ToggleFlag flags the hidden cell or removes the flag from the flagged cell.
+// If the game is completed, then ToggleFlag will panic.
+// If the game is paused, then ToggleFlag panics, the game must be resumed first.
+// If supplied i row and j column can not address a cell in the game or the
+// cell is visible, then ToggleFlag panics.
+func (g *Game) ToggleFlag(i, j int) {
+	if g.Completed() {
+		panic("game over")
 	}
-	if a.column < 0 {
-		panic("column must not be negative")
+
+	cell := getCell(g.board, cellAddress{row: i, column: j})
+	if cell == nil {
+		panic("non-existing cell addressed")
 	}
 
-	addresses := make([]cellAddress, 0, 8)
+	if cell.State == VisibleState {
+		panic("cell already visible")
+	}
 
-	rowMin := a.row - 1
-	if rowMin < 0 {
-		rowMin = 0
+	g.startMove()
+	switch cell.State {
+	case HiddenState:
+		cell.State = FlaggedState
+	case FlaggedState:
+		cell.State = HiddenState
 	}
-	rowMax := a.row + 1
 
-	columnMin := a.column - 1
-	if columnMin < 0 {
-		columnMin = 0
+	g.record(cellAddress{row: i, column: j})
+	g.emit(CellFlagged{Row: i, Column: j, Flagged: cell.State == FlaggedState})
+}
+
+// getCell returns the cell at the address. If the cell does not exist or is
+// absent, then nil is returned.
+func getCell(board [][]Cell, a cellAddress) *Cell {
+	if a.row < 0 || a.row >= len(board) {
+		return nil
+	}
+	if a.column < 0 || a.column >= len(board[a.row]) {
+		return nil
+	}
+	if board[a.row][a.column].State == AbsentState {
+		return nil
 	}
-	columnMax := a.column + 1
 
-	for i := rowMin; i <= rowMax; i++ {
-		for j := columnMin; j <= columnMax; j++ {
-			if i == a.row && j == a.column {
-				continue
-			}
+	return &board[a.row][a.column]
+}
 
-			address := cellAddress{
-				row:    i,
-				column: j,
-			}
+// revealSurrounding opens contiguous space of cells surrounding the empty
+// cell. It returns revealed with addresses of opened cells appended.
+func (g *Game) revealSurrounding(ca cellAddress, revealed []cellAddress) []cellAddress {
+	for _, currentAddress := range g.Topology().neighbours(g.board, ca) {
+		cell := getCell(g.board, currentAddress)
 
-			addresses = append(addresses, address)
+		if cell.State != HiddenState {
+			continue
+		}
+
+		if cell.Content == BlackHoleCellValue {
+			panic("unexpected condition: revealing naboring black hole")
+		}
+
+		cell.State = VisibleState
+		revealed = append(revealed, currentAddress)
+
+		if cell.Content == ZeroCellValue {
+			revealed = g.revealSurrounding(currentAddress, revealed)
 		}
 	}
 
-	return addresses
+	return revealed
 }
 
 func createGameState(rows int, columns int, defaultCell Cell) [][]Cell {
+	cells := make([]Cell, rows*columns)
+	for i := range cells {
+		cells[i] = defaultCell
+	}
+
 	stateRows := make([][]Cell, rows)
 	for rowNumber := range stateRows {
-		row := make([]Cell, columns)
-		for i := range row {
-			row[i] = defaultCell
-		}
-		stateRows[rowNumber] = row
+		stateRows[rowNumber] = cells[rowNumber*columns : (rowNumber+1)*columns : (rowNumber+1)*columns]
 	}
 
 	return stateRows
@@ -234,34 +519,25 @@ func replaceCells(board [][]Cell, addresses []cellAddress, defaultCell Cell) {
 	}
 }
 
-func generateBlackHoleAddresses(rows int, columns int, amount int) []cellAddress {
+// generateBlackHoleAddresses returns amount of random addresses of the board
+// of rows and columns. Addresses are picked by pickAddresses like on shaped
+// boards, so the same seed makes the same layout on boards of the same cells.
+func generateBlackHoleAddresses(rnd intner, rows int, columns int, amount int) []cellAddress {
 	if rows < 0 {
 		panic("rows must not be negative")
 	}
 	if columns < 0 {
 		panic("columns must not be negative")
 	}
-	if amount < 0 {
-		panic("amount must not be negative")
-	}
-	if amount > rows*columns {
-		panic("number of black holes does not fit in game board")
-	}
-
-	addresses := make([]cellAddress, 0, amount)
 
-	for len(addresses) < amount {
-		address := cellAddress{
-			row:    rand.Intn(rows),
-			column: rand.Intn(columns),
+	candidates := make([]cellAddress, 0, rows*columns)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < columns; j++ {
+			candidates = append(candidates, cellAddress{row: i, column: j})
 		}
-		if addressInList(address, addresses) {
-			continue
-		}
-		addresses = append(addresses, address)
 	}
 
-	return addresses
+	return pickAddresses(rnd, candidates, amount)
 }
 
 func addressInList(address cellAddress, list []cellAddress) bool {
@@ -274,54 +550,29 @@ func addressInList(address cellAddress, list []cellAddress) bool {
 	return false
 }
 
-func updateNaboringBlackHolesCellValues(board [][]Cell) {
+func updateNaboringBlackHolesCellValues(board [][]Cell, t Topology) {
 	for i, rows := range board {
 		for j := range rows {
 			cell := &board[i][j]
 
-			if cell.Content == BlackHoleCellValue {
+			if cell.Content == BlackHoleCellValue || cell.State == AbsentState {
 				continue
 			}
 
-			cell.Content = calculateNaboringBlackHoles(board, cellAddress{row: i, column: j})
+			cell.Content = calculateNaboringBlackHoles(board, t, cellAddress{row: i, column: j})
 		}
 	}
 }
 
-func calculateNaboringBlackHoles(board [][]Cell, a cellAddress) CellValue {
+func calculateNaboringBlackHoles(board [][]Cell, t Topology, a cellAddress) CellValue {
 	count := 0
-	for _, currentAddress := range surroundingAddresses(a) {
+	for _, currentAddress := range t.neighbours(board, a) {
 		cell := getCell(board, currentAddress)
 
-		if cell == nil {
-			continue
-		}
-
 		if cell.Content == BlackHoleCellValue {
 			count++
 		}
 	}
 
-	switch count {
-	case 0:
-		return ZeroCellValue
-	case 1:
-		return OneCellValue
-	case 2:
-		return TwoCellValue
-	case 3:
-		return ThreeCellValue
-	case 4:
-		return FourCellValue
-	case 5:
-		return FiveCellValue
-	case 6:
-		return SixCellValue
-	case 7:
-		return SevenCellValue
-	case 8:
-		return EightCellValue
-	default:
-		panic("unexpected counted surrounding black holes: " + fmt.Sprint(count))
-	}
+	return CellValue(count)
 }
@@ -2,6 +2,7 @@ package game
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -132,103 +133,38 @@ func TestGame_Won(t *testing.T) {
 func TestGame_RevealCell(t *testing.T) {
 	tests := []struct {
 		name             string
-		game             *Game
+		board            string
 		invokeRevealCell func(g *Game)
-		wantBoard        [][]Cell
+		wantBoard        string
 		wantLost         bool
 		wantWon          bool
 		wantCompleted    bool
 	}{
 		{
-			name: "lost right away",
-			game: func() *Game {
-				board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
-				blackHoleAddresses := []cellAddress{
-					{row: 0, column: 0},
-					{row: 1, column: 1},
-					{row: 2, column: 0},
-				}
-				replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
-				updateNaboringBlackHolesCellValues(board)
-
-				return &Game{failAt: nil, board: board}
-			}(),
+			name:  "lost right away",
+			board: "*..\n.*.\n*..\n",
 			invokeRevealCell: func(g *Game) {
 				g.RevealCell(1, 1)
 			},
-			wantBoard: [][]Cell{
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: TwoCellValue, State: HiddenState},
-					{Content: OneCellValue, State: HiddenState},
-				},
-				{
-					{Content: ThreeCellValue, State: HiddenState},
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: OneCellValue, State: HiddenState},
-				},
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: TwoCellValue, State: HiddenState},
-					{Content: OneCellValue, State: HiddenState},
-				},
-			},
+			wantBoard:     "*..\n.@.\n*..\n",
 			wantLost:      true,
 			wantWon:       false,
 			wantCompleted: true,
 		},
 		{
-			name: "reveal cell",
-			game: func() *Game {
-				board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
-				blackHoleAddresses := []cellAddress{
-					{row: 0, column: 0},
-					{row: 1, column: 1},
-					{row: 2, column: 0},
-				}
-				replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
-				updateNaboringBlackHolesCellValues(board)
-
-				return &Game{failAt: nil, board: board}
-			}(),
+			name:  "reveal cell",
+			board: "*..\n.*.\n*..\n",
 			invokeRevealCell: func(g *Game) {
 				g.RevealCell(1, 0)
 			},
-			wantBoard: [][]Cell{
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: TwoCellValue, State: HiddenState},
-					{Content: OneCellValue, State: HiddenState},
-				},
-				{
-					{Content: ThreeCellValue, State: VisibleState},
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: OneCellValue, State: HiddenState},
-				},
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: TwoCellValue, State: HiddenState},
-					{Content: OneCellValue, State: HiddenState},
-				},
-			},
+			wantBoard:     "*..\n3*.\n*..\n",
 			wantLost:      false,
 			wantWon:       false,
 			wantCompleted: false,
 		},
 		{
-			name: "won on revealing",
-			game: func() *Game {
-				board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
-				blackHoleAddresses := []cellAddress{
-					{row: 0, column: 0},
-					{row: 1, column: 1},
-					{row: 2, column: 0},
-				}
-				replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
-				updateNaboringBlackHolesCellValues(board)
-
-				return &Game{failAt: nil, board: board}
-			}(),
+			name:  "won on revealing",
+			board: "*..\n.*.\n*..\n",
 			invokeRevealCell: func(g *Game) {
 				g.RevealCell(0, 1)
 				g.RevealCell(0, 2)
@@ -237,59 +173,18 @@ func TestGame_RevealCell(t *testing.T) {
 				g.RevealCell(2, 1)
 				g.RevealCell(2, 2)
 			},
-			wantBoard: [][]Cell{
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: TwoCellValue, State: VisibleState},
-					{Content: OneCellValue, State: VisibleState},
-				},
-				{
-					{Content: ThreeCellValue, State: VisibleState},
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: OneCellValue, State: VisibleState},
-				},
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: TwoCellValue, State: VisibleState},
-					{Content: OneCellValue, State: VisibleState},
-				},
-			},
+			wantBoard:     "*21\n3*1\n*21\n",
 			wantLost:      false,
 			wantWon:       true,
 			wantCompleted: true,
 		},
 		{
-			name: "open contiguos space of cells not bordering with black holes",
-			game: func() *Game {
-				board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
-				blackHoleAddresses := []cellAddress{
-					{row: 0, column: 0},
-				}
-				replaceCells(board, blackHoleAddresses, Cell{Content: BlackHoleCellValue, State: HiddenState})
-				updateNaboringBlackHolesCellValues(board)
-
-				return &Game{failAt: nil, board: board}
-			}(),
+			name:  "open contiguos space of cells not bordering with black holes",
+			board: "*..\n...\n...\n",
 			invokeRevealCell: func(g *Game) {
 				g.RevealCell(0, 2)
 			},
-			wantBoard: [][]Cell{
-				{
-					{Content: BlackHoleCellValue, State: HiddenState},
-					{Content: OneCellValue, State: VisibleState},
-					{Content: ZeroCellValue, State: VisibleState},
-				},
-				{
-					{Content: OneCellValue, State: VisibleState},
-					{Content: OneCellValue, State: VisibleState},
-					{Content: ZeroCellValue, State: VisibleState},
-				},
-				{
-					{Content: ZeroCellValue, State: VisibleState},
-					{Content: ZeroCellValue, State: VisibleState},
-					{Content: ZeroCellValue, State: VisibleState},
-				},
-			},
+			wantBoard:     "*10\n110\n000\n",
 			wantLost:      false,
 			wantWon:       true,
 			wantCompleted: true,
@@ -297,12 +192,14 @@ func TestGame_RevealCell(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.invokeRevealCell(tt.game)
+			game := parseBoard(t, tt.board)
+
+			tt.invokeRevealCell(game)
 
-			gotBoard := tt.game.GetState()
-			gotLost := tt.game.Lost()
-			gotWon := tt.game.Won()
-			gotCompleted := tt.game.Completed()
+			gotBoard := FormatBoard(game)
+			gotLost := game.Lost()
+			gotWon := game.Won()
+			gotCompleted := game.Completed()
 
 			assert.Equal(t, tt.wantBoard, gotBoard)
 			assert.Equal(t, tt.wantLost, gotLost)
@@ -312,6 +209,180 @@ func TestGame_RevealCell(t *testing.T) {
 	}
 }
 
+func TestGame_RevealCell_flagged(t *testing.T) {
+	board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
+	replaceCells(board, []cellAddress{{row: 0, column: 0}}, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, Square{})
+	game := &Game{failAt: nil, board: board}
+
+	game.ToggleFlag(0, 1)
+	game.ToggleFlag(1, 1)
+	game.RevealCell(2, 2)
+
+	assert.Equal(t, [][]Cell{
+		{
+			{Content: BlackHoleCellValue, State: HiddenState},
+			{Content: OneCellValue, State: FlaggedState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+		{
+			{Content: OneCellValue, State: VisibleState},
+			{Content: OneCellValue, State: FlaggedState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+		{
+			{Content: ZeroCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+	}, game.GetState())
+	assert.False(t, game.Completed())
+	assert.PanicsWithValue(t, "cell flagged", func() { game.RevealCell(0, 1) })
+}
+
+func TestGame_ToggleFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		game      *Game
+		row       int
+		column    int
+		wantState CellState
+		wantPanic string
+	}{
+		{
+			name: "flag hidden cell",
+			game: &Game{
+				board: [][]Cell{{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: HiddenState}}},
+			},
+			row:       0,
+			column:    0,
+			wantState: FlaggedState,
+		},
+		{
+			name: "remove flag",
+			game: &Game{
+				board: [][]Cell{{{Content: BlackHoleCellValue, State: FlaggedState}, {Content: OneCellValue, State: HiddenState}}},
+			},
+			row:       0,
+			column:    0,
+			wantState: HiddenState,
+		},
+		{
+			name: "visible cell",
+			game: &Game{
+				board: [][]Cell{{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: VisibleState}, {Content: OneCellValue, State: HiddenState}}},
+			},
+			row:       0,
+			column:    1,
+			wantPanic: "cell already visible",
+		},
+		{
+			name: "non-existing cell",
+			game: &Game{
+				board: [][]Cell{{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: HiddenState}}},
+			},
+			row:       1,
+			column:    0,
+			wantPanic: "non-existing cell addressed",
+		},
+		{
+			name: "game over",
+			game: &Game{
+				failAt: &cellAddress{row: 0, column: 0},
+				board:  [][]Cell{{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: HiddenState}}},
+			},
+			row:       0,
+			column:    1,
+			wantPanic: "game over",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantPanic != "" {
+				assert.PanicsWithValue(t, tt.wantPanic, func() { tt.game.ToggleFlag(tt.row, tt.column) })
+
+				return
+			}
+
+			tt.game.ToggleFlag(tt.row, tt.column)
+
+			assert.Equal(t, tt.wantState, tt.game.GetState()[tt.row][tt.column].State)
+		})
+	}
+}
+
+func TestGame_ChordCell(t *testing.T) {
+	tests := []struct {
+		name      string
+		board     string
+		invoke    func(g *Game)
+		wantBoard string
+		wantLost  bool
+		wantWon   bool
+	}{
+		{
+			name:  "flags match cell value",
+			board: "*..\n...\n*..\n",
+			invoke: func(g *Game) {
+				g.RevealCell(1, 1)
+				g.ToggleFlag(0, 0)
+				g.ToggleFlag(2, 0)
+				g.ChordCell(1, 1)
+			},
+			wantBoard: "F10\n220\nF10\n",
+			wantWon:   true,
+		},
+		{
+			name:  "flags do not match cell value",
+			board: "*..\n...\n*..\n",
+			invoke: func(g *Game) {
+				g.RevealCell(1, 1)
+				g.ToggleFlag(0, 0)
+				g.ChordCell(1, 1)
+			},
+			wantBoard: "F..\n.2.\n*..\n",
+		},
+		{
+			name:  "wrong flag",
+			board: "*..\n...\n*..\n",
+			invoke: func(g *Game) {
+				g.RevealCell(1, 1)
+				g.ToggleFlag(0, 0)
+				g.ToggleFlag(1, 0)
+				g.ChordCell(1, 1)
+			},
+			wantBoard: "F10\nX20\n@10\n",
+			wantLost:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := parseBoard(t, tt.board)
+
+			tt.invoke(game)
+
+			assert.Equal(t, tt.wantBoard, FormatBoard(game))
+			assert.Equal(t, tt.wantLost, game.Lost())
+			assert.Equal(t, tt.wantWon, game.Won())
+		})
+	}
+}
+
+func TestGame_FailedAt(t *testing.T) {
+	board := [][]Cell{{{Content: OneCellValue, State: HiddenState}, {Content: BlackHoleCellValue, State: HiddenState}}}
+	game := &Game{failAt: nil, board: board}
+
+	_, _, failed := game.FailedAt()
+	assert.False(t, failed)
+
+	game.RevealCell(0, 1)
+
+	row, column, failed := game.FailedAt()
+	assert.True(t, failed)
+	assert.Equal(t, 0, row)
+	assert.Equal(t, 1, column)
+}
+
 func TestGame_Completed(t *testing.T) {
 	tests := []struct {
 		name string
@@ -363,6 +434,91 @@ func TestGame_Completed(t *testing.T) {
 	}
 }
 
+func TestGame_Restart(t *testing.T) {
+	g := parseBoard(t, "@F.\n 22\n", WithTopology(Torus{}), WithSeed(7))
+
+	restarted := g.Restart()
+
+	assert.Equal(t, "**.\n ..\n", FormatBoard(restarted))
+	assert.Equal(t, Torus{}, restarted.Topology())
+	seed, ok := restarted.Seed()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), seed)
+	assert.Equal(t, NotStarted, restarted.Status())
+	assert.Equal(t, "@F.\n 22\n", FormatBoard(g))
+
+	restarted.RevealCell(1, 1)
+	assert.Equal(t, Lost, g.Status())
+	assert.Equal(t, InProgress, restarted.Status())
+}
+
+func TestGame_Clone(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	g := parseBoard(t, "*...\n....\n*...\n", WithClock(clock.Now), WithSeed(7))
+	g.ToggleFlag(0, 0)
+	g.RevealCell(0, 3)
+	clock.advance(time.Minute)
+	var events []Event
+	g.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	clone := g.Clone()
+
+	assert.Equal(t, FormatBoard(g), FormatBoard(clone))
+	assert.Equal(t, g.Version(), clone.Version())
+	assert.Equal(t, g.Status(), clone.Status())
+	assert.Equal(t, time.Minute, clone.Elapsed())
+	gotChanges, _ := clone.ChangesSince(0)
+	wantChanges, _ := g.ChangesSince(0)
+	assert.Equal(t, wantChanges, gotChanges)
+	seed, _ := clone.Seed()
+	assert.Equal(t, uint64(7), seed)
+
+	original := FormatBoard(g)
+	version := g.Version()
+
+	clone.ToggleFlag(0, 0)
+	clone.ToggleFlag(1, 0)
+	clone.Pause()
+	clock.advance(time.Minute)
+	clone.Resume()
+	clone.RevealCell(2, 0)
+
+	assert.Equal(t, original, FormatBoard(g))
+	assert.Equal(t, version, g.Version())
+	assert.Equal(t, InProgress, g.Status())
+	assert.Equal(t, 2*time.Minute, g.Elapsed())
+	_, _, failed := g.FailedAt()
+	assert.False(t, failed)
+	changes, _ := g.ChangesSince(0)
+	assert.Equal(t, wantChanges, changes)
+	assert.Empty(t, events)
+
+	assert.Equal(t, Lost, clone.Status())
+	assert.Equal(t, time.Minute, clone.Elapsed())
+	assert.NoError(t, g.Validate())
+	assert.NoError(t, clone.Validate())
+
+	g.Resign()
+	row, column, _ := clone.FailedAt()
+	assert.Equal(t, 2, row)
+	assert.Equal(t, 0, column)
+	assert.Equal(t, Lost, clone.Status())
+}
+
+func TestGame_Clone_failed(t *testing.T) {
+	g := parseBoard(t, "@..\n...\n")
+
+	clone := g.Clone()
+	clone.failAt.column = 1
+
+	row, column, failed := g.FailedAt()
+	assert.True(t, failed)
+	assert.Equal(t, 0, row)
+	assert.Equal(t, 0, column)
+}
+
 func Test_calculateNaboringBlackHoles(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -464,7 +620,7 @@ func Test_calculateNaboringBlackHoles(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := calculateNaboringBlackHoles(tt.board, tt.cellAddress)
+			got := calculateNaboringBlackHoles(tt.board, Square{}, tt.cellAddress)
 
 			if tt.want != got {
 				t.Errorf("Want: [%d], got: [%d]", tt.want, got)
@@ -482,7 +638,7 @@ func Fuzz_generateBlackHoleAddresses(f *testing.F) {
 		if amount > row*column {
 			return
 		}
-		got := generateBlackHoleAddresses(row, column, amount)
+		got := generateBlackHoleAddresses(globalRand{}, row, column, amount)
 		if len(got) != amount {
 			t.Errorf("Want generated black hole addresses: %d, got: %d", amount, len(got))
 		}
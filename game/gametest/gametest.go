@@ -0,0 +1,111 @@
+// Package gametest provides helpers for tests of code using the game package:
+// games built from the board text format, assertions of cells and boards,
+// golden files of rendered boards and scripted moves.
+package gametest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// Lines joins lines of the board text format, so boards may be written one row
+// per argument.
+func Lines(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// NewGame returns the game of the board in the text format of
+// game.ParseBoard. The test fails immediately if the board is invalid.
+func NewGame(t testing.TB, board string, options ...game.Option) *game.Game {
+	t.Helper()
+
+	g, err := game.ParseBoard(strings.NewReader(board), options...)
+	if err != nil {
+		t.Fatalf("invalid board:\n%s\n%v", board, err)
+	}
+
+	return g
+}
+
+// AssertState asserts that the cell at the row and column is in the state.
+// It returns true if the assertion holds.
+func AssertState(t testing.TB, g *game.Game, row, column int, want game.CellState) bool {
+	t.Helper()
+
+	state := g.GetState()
+	if row < 0 || row >= len(state) || column < 0 || column >= len(state[row]) {
+		t.Errorf("cell %d:%d is out of the %s board", row, column, size(state))
+
+		return false
+	}
+
+	if got := state[row][column].State; got != want {
+		t.Errorf("cell %d:%d is %s, want %s", row, column, StateName(got), StateName(want))
+
+		return false
+	}
+
+	return true
+}
+
+// AssertVisible asserts that the cell at the row and column is visible.
+func AssertVisible(t testing.TB, g *game.Game, row, column int) bool {
+	t.Helper()
+
+	return AssertState(t, g, row, column, game.VisibleState)
+}
+
+// AssertHidden asserts that the cell at the row and column is hidden.
+func AssertHidden(t testing.TB, g *game.Game, row, column int) bool {
+	t.Helper()
+
+	return AssertState(t, g, row, column, game.HiddenState)
+}
+
+// AssertFlagged asserts that the cell at the row and column is flagged.
+func AssertFlagged(t testing.TB, g *game.Game, row, column int) bool {
+	t.Helper()
+
+	return AssertState(t, g, row, column, game.FlaggedState)
+}
+
+// AssertBoard asserts that the game is the board in the text format of
+// game.FormatBoard. The final line break of the board may be omitted.
+func AssertBoard(t testing.TB, g *game.Game, want string) bool {
+	t.Helper()
+
+	if !strings.HasSuffix(want, "\n") {
+		want += "\n"
+	}
+
+	return assert.Equal(t, want, game.FormatBoard(g))
+}
+
+// StateName returns the name of the cell state used in failure messages.
+func StateName(s game.CellState) string {
+	switch s {
+	case game.HiddenState:
+		return "hidden"
+	case game.VisibleState:
+		return "visible"
+	case game.FlaggedState:
+		return "flagged"
+	case game.AbsentState:
+		return "absent"
+	default:
+		return fmt.Sprintf("state %d", s)
+	}
+}
+
+func size(state [][]game.Cell) string {
+	columns := 0
+	if len(state) > 0 {
+		columns = len(state[0])
+	}
+
+	return fmt.Sprintf("%dx%d", len(state), columns)
+}
@@ -0,0 +1,174 @@
+package gametest
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records failures of helpers instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.FailNow()
+}
+
+func (r *recorder) FailNow() {
+	runtime.Goexit()
+}
+
+// failures returns failures reported by fn. Failing fn immediately stops it,
+// as it stops tests.
+func failures(t *testing.T, fn func(t testing.TB)) []string {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+
+	return r.failures
+}
+
+func TestNewGame(t *testing.T) {
+	g := NewGame(t, Lines("*1.", "...", " .."), game.WithTopology(game.Torus{}))
+	assert.Equal(t, game.Torus{}, g.Topology())
+	assert.Equal(t, "*1.\n...\n ..\n", game.FormatBoard(g))
+
+	got := failures(t, func(t testing.TB) {
+		NewGame(t, "*0\n")
+		t.Errorf("not stopped")
+	})
+	assert.Equal(t, []string{"invalid board:\n*0\n\nline 1 column 2: cell neighbours 1 black holes"}, got)
+}
+
+func TestAssertState(t *testing.T) {
+	g := NewGame(t, "F1.\n...\n")
+
+	assert.True(t, AssertFlagged(t, g, 0, 0))
+	assert.True(t, AssertVisible(t, g, 0, 1))
+	assert.True(t, AssertHidden(t, g, 1, 2))
+
+	got := failures(t, func(t testing.TB) {
+		AssertVisible(t, g, 0, 0)
+		AssertHidden(t, g, 2, 0)
+		AssertState(t, g, 1, 1, game.AbsentState)
+	})
+	assert.Equal(t, []string{
+		"cell 0:0 is flagged, want visible",
+		"cell 2:0 is out of the 2x3 board",
+		"cell 1:1 is hidden, want absent",
+	}, got)
+}
+
+func TestAssertBoard(t *testing.T) {
+	g := NewGame(t, "*..\n...\n")
+	g.RevealCell(1, 2)
+
+	assert.True(t, AssertBoard(t, g, "*10\n.10"))
+	assert.Len(t, failures(t, func(t testing.TB) { AssertBoard(t, g, "*..\n...\n") }), 1)
+}
+
+func TestAssertGolden(t *testing.T) {
+	g := NewGame(t, Lines("F2.", "@2.", " 1."))
+	assert.True(t, AssertGoldenBoard(t, "testdata/lost.golden", g))
+
+	// the test runs the same with and without GAMETEST_UPDATE
+	defer func(updated bool) { Update = updated }(Update)
+	Update = false
+
+	path := filepath.Join(t.TempDir(), "board.golden")
+	got := failures(t, func(t testing.TB) { AssertGoldenBoard(t, path, g) })
+	assert.Equal(t, []string{"golden file " + path + " does not exist, run tests with GAMETEST_UPDATE=1 to write it"}, got)
+
+	Update = true
+	require.True(t, AssertGoldenBoard(t, path, g))
+	Update = false
+	assert.True(t, AssertGoldenBoard(t, path, g))
+	assert.Len(t, failures(t, func(t testing.TB) { AssertGolden(t, path, []byte("F2.\n")) }), 1)
+}
+
+func TestPlay(t *testing.T) {
+	g := NewGame(t, "*..\n...\n*..\n")
+
+	Play(t, g, `
+		# opening
+		reveal 1 2
+		flag 0 0
+		pause
+		! reveal 1 0
+		resume
+		chord 1 1
+		resign
+		! resign
+	`)
+	AssertBoard(t, g, "F10\n.20\n*10\n")
+	assert.Equal(t, game.Resigned, g.Status())
+}
+
+func TestPlay_failures(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{
+			name:   "unknown step",
+			script: "jump 1 1",
+			want:   `script line 1 "jump 1 1": unknown step "jump"`,
+		},
+		{
+			name:   "missing cell",
+			script: "\nreveal 1",
+			want:   `script line 2 "reveal 1": reveal takes the row and column of the cell`,
+		},
+		{
+			name:   "status with cell",
+			script: "pause 1 1",
+			want:   `script line 1 "pause 1 1": pause takes no cell`,
+		},
+		{
+			name:   "invalid row",
+			script: "flag a 1",
+			want:   `script line 1 "flag a 1": row: strconv.Atoi: parsing "a": invalid syntax`,
+		},
+		{
+			name:   "unexpected panic",
+			script: "reveal 0 1\nreveal 0 1",
+			want:   "script line 2 \"reveal 0 1\" panicked: cell already visible\n*1.\n",
+		},
+		{
+			name:   "missing panic",
+			script: "! flag 0 0",
+			want:   "script line 1 \"! flag 0 0\" did not panic\nF..\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGame(t, "*..\n")
+
+			got := failures(t, func(t testing.TB) {
+				Play(t, g, tt.script)
+				t.Errorf("not stopped")
+			})
+
+			assert.Equal(t, []string{tt.want}, got)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package gametest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// Update makes AssertGolden write golden files instead of comparing them. It
+// is set if tests are run with the GAMETEST_UPDATE environment variable, and
+// tests may set it from flags of their own. The package registers no flags,
+// so it does not clash with flags of packages importing it.
+var Update = os.Getenv("GAMETEST_UPDATE") != ""
+
+// AssertGolden asserts that got equals the content of the golden file at the
+// path. If Update is set, then the file is written with got instead, so golden
+// files are made and refreshed by tests.
+func AssertGolden(t testing.TB, path string, got []byte) bool {
+	t.Helper()
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden file %s: %v", path, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("golden file %s: %v", path, err)
+		}
+
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("golden file %s does not exist, run tests with GAMETEST_UPDATE=1 to write it", path)
+
+		return false
+	}
+	if err != nil {
+		t.Fatalf("golden file %s: %v", path, err)
+	}
+
+	return assert.Equal(t, string(want), string(got), "golden file %s", path)
+}
+
+// AssertGoldenBoard asserts that the game formatted by game.FormatBoard equals
+// the golden file at the path.
+func AssertGoldenBoard(t testing.TB, path string, g *game.Game) bool {
+	t.Helper()
+
+	return AssertGolden(t, path, []byte(game.FormatBoard(g)))
+}
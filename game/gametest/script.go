@@ -0,0 +1,107 @@
+package gametest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kalynv/proxx/game"
+)
+
+// Play makes moves of the script on the game. Every line of the script is
+// one step: "reveal", "flag" or "chord" followed by the row and column of the
+// cell, or "pause", "resume" or "resign". Empty lines and lines starting with
+// '#' are skipped. Steps prefixed with '!' are expected to panic, such as
+// moves of completed games.
+// The test fails immediately at the first invalid step, at the first step
+// panicking unexpectedly and at the first step not panicking as expected.
+func Play(t testing.TB, g *game.Game, script string) {
+	t.Helper()
+
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		expectPanic := strings.HasPrefix(line, "!")
+		step, err := parseStep(strings.TrimSpace(strings.TrimPrefix(line, "!")))
+		if err != nil {
+			t.Fatalf("script line %d %q: %v", n, line, err)
+		}
+
+		panicked := tryStep(g, step)
+		switch {
+		case panicked != nil && !expectPanic:
+			t.Fatalf("script line %d %q panicked: %v\n%s", n, line, panicked, game.FormatBoard(g))
+		case panicked == nil && expectPanic:
+			t.Fatalf("script line %d %q did not panic\n%s", n, line, game.FormatBoard(g))
+		}
+	}
+}
+
+// tryStep makes the step and returns the value of its panic.
+func tryStep(g *game.Game, step func(g *game.Game)) (panicked interface{}) {
+	defer func() {
+		panicked = recover()
+	}()
+
+	step(g)
+
+	return nil
+}
+
+// parseStep returns the step of the script line.
+func parseStep(line string) (func(g *game.Game), error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, errors.New("empty step")
+	}
+
+	var kind game.MoveKind
+	switch fields[0] {
+	case "reveal":
+		kind = game.RevealMove
+	case "flag":
+		kind = game.FlagMove
+	case "chord":
+		kind = game.ChordMove
+	case "pause":
+		return statusStep(fields, (*game.Game).Pause)
+	case "resume":
+		return statusStep(fields, (*game.Game).Resume)
+	case "resign":
+		return statusStep(fields, (*game.Game).Resign)
+	default:
+		return nil, fmt.Errorf("unknown step %q", fields[0])
+	}
+
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("%s takes the row and column of the cell", fields[0])
+	}
+	row, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("row: %w", err)
+	}
+	column, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("column: %w", err)
+	}
+
+	move := game.Move{Kind: kind, Row: row, Column: column}
+
+	return func(g *game.Game) { g.Play(move) }, nil
+}
+
+// statusStep returns the step changing the game status, which takes no cell.
+func statusStep(fields []string, step func(g *game.Game)) (func(g *game.Game), error) {
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("%s takes no cell", fields[0])
+	}
+
+	return step, nil
+}
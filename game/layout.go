@@ -0,0 +1,80 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPositionOutOfRange is returned for black holes placed out of the
+	// board.
+	ErrPositionOutOfRange = errors.New("position out of range")
+	// ErrDuplicatePosition is returned for black holes placed twice.
+	ErrDuplicatePosition = errors.New("duplicate position")
+	// ErrTopologyMismatch is returned for boards whose size does not match
+	// the Grid topology.
+	ErrTopologyMismatch = errors.New("board does not match the topology")
+)
+
+// Position is the row and column of a cell.
+type Position struct {
+	Row    int
+	Column int
+}
+
+// NewGameFromLayout returns the game of rows and columns with black holes at
+// the positions. Numbers of cells are computed for the topology given by the
+// options.
+// Errors wrapping ErrPositionOutOfRange or ErrDuplicatePosition are returned
+// for positions out of the board or listed twice, and errors wrapping
+// ErrTopologyMismatch for boards not matching the Grid topology.
+func NewGameFromLayout(rows, columns int, blackHoles []Position, options ...Option) (*Game, error) {
+	if rows < 0 || columns < 0 {
+		return nil, fmt.Errorf("board of %d rows and %d columns: negative size", rows, columns)
+	}
+
+	o := newOptions(options)
+	if err := o.checkBoard(rows, columns); err != nil {
+		return nil, err
+	}
+
+	board := createGameState(rows, columns, Cell{Content: ZeroCellValue, State: HiddenState})
+	for _, p := range blackHoles {
+		cell := getCell(board, cellAddress{row: p.Row, column: p.Column})
+		if cell == nil {
+			return nil, fmt.Errorf("black hole at %d:%d: %w", p.Row, p.Column, ErrPositionOutOfRange)
+		}
+		if cell.Content == BlackHoleCellValue {
+			return nil, fmt.Errorf("black hole at %d:%d: %w", p.Row, p.Column, ErrDuplicatePosition)
+		}
+
+		cell.Content = BlackHoleCellValue
+	}
+	updateNaboringBlackHolesCellValues(board, o.topology)
+
+	return o.newGame(board), nil
+}
+
+// NewGameFromMask returns the game with black holes at true cells of the
+// mask. All rows of the mask must be of the same length.
+func NewGameFromMask(blackHoles [][]bool, options ...Option) (*Game, error) {
+	columns := 0
+	if len(blackHoles) > 0 {
+		columns = len(blackHoles[0])
+	}
+
+	var positions []Position
+	for i, row := range blackHoles {
+		if len(row) != columns {
+			return nil, fmt.Errorf("mask row %d has %d columns, want %d", i, len(row), columns)
+		}
+
+		for j, blackHole := range row {
+			if blackHole {
+				positions = append(positions, Position{Row: i, Column: j})
+			}
+		}
+	}
+
+	return NewGameFromLayout(len(blackHoles), columns, positions, options...)
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGameFromLayout(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       int
+		columns    int
+		blackHoles []Position
+		options    []Option
+		want       [][]Cell
+		wantErr    error
+	}{
+		{
+			name:       "square",
+			rows:       2,
+			columns:    3,
+			blackHoles: []Position{{Row: 0, Column: 0}, {Row: 1, Column: 2}},
+			want: [][]Cell{
+				{{Content: BlackHoleCellValue}, {Content: TwoCellValue}, {Content: OneCellValue}},
+				{{Content: OneCellValue}, {Content: TwoCellValue}, {Content: BlackHoleCellValue}},
+			},
+		},
+		{
+			name:       "torus",
+			rows:       1,
+			columns:    3,
+			blackHoles: []Position{{Row: 0, Column: 0}},
+			options:    []Option{WithTopology(Torus{})},
+			want: [][]Cell{
+				{{Content: BlackHoleCellValue}, {Content: OneCellValue}, {Content: OneCellValue}},
+			},
+		},
+		{
+			name:       "out of range",
+			rows:       2,
+			columns:    2,
+			blackHoles: []Position{{Row: 2, Column: 0}},
+			wantErr:    ErrPositionOutOfRange,
+		},
+		{
+			name:       "negative position",
+			rows:       2,
+			columns:    2,
+			blackHoles: []Position{{Row: 0, Column: -1}},
+			wantErr:    ErrPositionOutOfRange,
+		},
+		{
+			name:       "duplicate",
+			rows:       2,
+			columns:    2,
+			blackHoles: []Position{{Row: 1, Column: 1}, {Row: 1, Column: 1}},
+			wantErr:    ErrDuplicatePosition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewGameFromLayout(tt.rows, tt.columns, tt.blackHoles, tt.options...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got.GetState())
+		})
+	}
+}
+
+func TestNewGameFromMask(t *testing.T) {
+	got, err := NewGameFromMask([][]bool{
+		{true, false},
+		{false, false},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]Cell{
+		{{Content: BlackHoleCellValue}, {Content: OneCellValue}},
+		{{Content: OneCellValue}, {Content: OneCellValue}},
+	}, got.GetState())
+
+	_, err = NewGameFromMask([][]bool{{true, false}, {false}})
+	assert.Error(t, err)
+}
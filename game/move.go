@@ -0,0 +1,35 @@
+package game
+
+// MoveKind is the kind of the move a player makes.
+type MoveKind int
+
+const (
+	// RevealMove reveals the cell by RevealCell.
+	RevealMove MoveKind = iota
+	// FlagMove toggles the flag of the cell by ToggleFlag.
+	FlagMove
+	// ChordMove reveals cells surrounding the cell by ChordCell.
+	ChordMove
+)
+
+// Move is the move of a player at Row and Column.
+type Move struct {
+	Kind   MoveKind
+	Row    int
+	Column int
+}
+
+// Play makes the move. It panics in the same cases as the method making the
+// move of the kind, and for unknown kinds.
+func (g *Game) Play(m Move) {
+	switch m.Kind {
+	case RevealMove:
+		g.RevealCell(m.Row, m.Column)
+	case FlagMove:
+		g.ToggleFlag(m.Row, m.Column)
+	case ChordMove:
+		g.ChordCell(m.Row, m.Column)
+	default:
+		panic("unknown move kind")
+	}
+}
@@ -0,0 +1,19 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_Play(t *testing.T) {
+	game := parseBoard(t, "*..\n...\n*..\n")
+
+	game.Play(Move{Kind: RevealMove, Row: 1, Column: 1})
+	game.Play(Move{Kind: FlagMove, Row: 0, Column: 0})
+	game.Play(Move{Kind: FlagMove, Row: 2, Column: 0})
+	game.Play(Move{Kind: ChordMove, Row: 1, Column: 1})
+
+	assert.Equal(t, "F10\n220\nF10\n", FormatBoard(game))
+	assert.Panics(t, func() { parseBoard(t, "..\n").Play(Move{Kind: MoveKind(9)}) })
+}
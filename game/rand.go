@@ -0,0 +1,66 @@
+package game
+
+import "math/rand"
+
+// intner returns random integers in [0, n).
+type intner interface {
+	Intn(n int) int
+}
+
+// globalRand is intner of the math/rand package. Its layouts can not be
+// reproduced.
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+// pcg32 is the PCG-XSH-RR generator with 64 bits of state and 32 bits of
+// output. Unlike math/rand, its sequences are fixed by the algorithm, so
+// seeded layouts stay the same across Go releases.
+type pcg32 struct {
+	state uint64
+	inc   uint64
+}
+
+const pcg32Multiplier = 6364136223846793005
+
+// newPCG32 returns the generator of the seed and the sequence selecting one of
+// 2^63 streams.
+func newPCG32(seed, sequence uint64) *pcg32 {
+	p := &pcg32{inc: sequence<<1 | 1}
+	p.next()
+	p.state += seed
+	p.next()
+
+	return p
+}
+
+func (p *pcg32) next() uint32 {
+	old := p.state
+	p.state = old*pcg32Multiplier + p.inc
+
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	rotation := uint32(old >> 59)
+
+	return xorShifted>>rotation | xorShifted<<((-rotation)&31)
+}
+
+// Intn returns an unbiased random integer in [0, n). If n is not in
+// [1, 2^32], then Intn panics.
+func (p *pcg32) Intn(n int) int {
+	if n < 1 || uint64(n) > 1<<32 {
+		panic("invalid argument to Intn")
+	}
+	if uint64(n) == 1<<32 {
+		return int(p.next())
+	}
+
+	bound := uint32(n)
+	threshold := -bound % bound
+	for {
+		if r := p.next(); r >= threshold {
+			return int(r % bound)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPCG32(t *testing.T) {
+	// the reference output of the PCG demo for seed 42 and sequence 54
+	p := newPCG32(42, 54)
+
+	got := make([]uint32, 6)
+	for i := range got {
+		got[i] = p.next()
+	}
+
+	assert.Equal(t, []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}, got)
+}
+
+func TestPCG32_Intn(t *testing.T) {
+	p := newPCG32(1, 0)
+	for i := 0; i < 1000; i++ {
+		n := p.Intn(7)
+		assert.True(t, n >= 0 && n < 7)
+	}
+
+	assert.Panics(t, func() { p.Intn(0) })
+}
+
+func TestWithSeed(t *testing.T) {
+	// layouts of seeds must never change, as stored seeds refer to them
+	tests := []struct {
+		name string
+		game *Game
+		want string
+	}{
+		{
+			name: "small board",
+			game: NewGame(5, 5, WithSeed(1)),
+			want: "..*..\n.....\n..*.*\n.....\n*..*.\n",
+		},
+		{
+			name: "beginner board",
+			game: NewGame(8, 10, WithSeed(20261018)),
+			want: "*.....*.\n......*.\n........\n......*.\n.......*\n..*.*...\n...*....\n.....*.*\n",
+		},
+		{
+			name: "rectangle shaped board",
+			game: NewShapedGame(NewShape(8, 8), 10, WithSeed(20261018)),
+			want: "*.....*.\n......*.\n........\n......*.\n.......*\n..*.*...\n...*....\n.....*.*\n",
+		},
+		{
+			name: "shaped board",
+			game: NewShapedGame(CircleShape(6), 8, WithSeed(7)),
+			want: " ...*\n.*.*..\n..**..\n.*....\n..*..*\n ....\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatBoard(tt.game))
+		})
+	}
+
+	seed, ok := NewGame(3, 1, WithSeed(42)).Seed()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), seed)
+
+	_, ok = NewGame(3, 1).Seed()
+	assert.False(t, ok)
+}
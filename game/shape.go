@@ -0,0 +1,184 @@
+package game
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Shape describes which cells of the rectangular board exist. Absent cells
+// hold no black holes, neighbour no cells and need not be revealed to win.
+type Shape struct {
+	rows    int
+	columns int
+	present []bool
+}
+
+// NewShape returns the full rectangle shape of rows and columns.
+// If rows or columns is negative, then NewShape panics.
+func NewShape(rows, columns int) Shape {
+	if rows < 0 {
+		panic("rows must not be negative")
+	}
+	if columns < 0 {
+		panic("columns must not be negative")
+	}
+
+	s := Shape{rows: rows, columns: columns, present: make([]bool, rows*columns)}
+	for i := range s.present {
+		s.present[i] = true
+	}
+
+	return s
+}
+
+// CircleShape returns the shape of the circle fitting the square board of the
+// diameter.
+func CircleShape(diameter int) Shape {
+	s := NewShape(diameter, diameter)
+
+	// cell centres are compared in doubled coordinates to stay in integers
+	for i := 0; i < diameter; i++ {
+		for j := 0; j < diameter; j++ {
+			di := 2*i + 1 - diameter
+			dj := 2*j + 1 - diameter
+			if di*di+dj*dj > diameter*diameter {
+				s.present[i*diameter+j] = false
+			}
+		}
+	}
+
+	return s
+}
+
+// ParseShape reads the shape from the ASCII template. Each line is a row of the
+// board, spaces are absent cells and any other characters are cells. Short
+// lines are padded with absent cells and trailing empty lines are ignored.
+func ParseShape(r io.Reader) (Shape, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return Shape{}, err
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	columns := 0
+	for _, line := range lines {
+		if len([]rune(line)) > columns {
+			columns = len([]rune(line))
+		}
+	}
+
+	s := Shape{rows: len(lines), columns: columns, present: make([]bool, len(lines)*columns)}
+	for i, line := range lines {
+		for j, r := range []rune(line) {
+			s.present[i*columns+j] = r != ' '
+		}
+	}
+
+	if s.Cells() == 0 {
+		return Shape{}, errors.New("shape has no cells")
+	}
+
+	return s, nil
+}
+
+// Rows returns the number of rows of the shape.
+func (s Shape) Rows() int {
+	return s.rows
+}
+
+// Columns returns the number of columns of the shape.
+func (s Shape) Columns() int {
+	return s.columns
+}
+
+// Has returns true if the cell at i row and j column exists. Otherwise false is
+// returned.
+func (s Shape) Has(i, j int) bool {
+	if i < 0 || i >= s.rows || j < 0 || j >= s.columns {
+		return false
+	}
+
+	return s.present[i*s.columns+j]
+}
+
+// Without returns the shape with the cell at i row and j column removed.
+func (s Shape) Without(i, j int) Shape {
+	present := make([]bool, len(s.present))
+	copy(present, s.present)
+	s.present = present
+
+	if s.Has(i, j) {
+		s.present[i*s.columns+j] = false
+	}
+
+	return s
+}
+
+// Cells returns the number of existing cells of the shape.
+func (s Shape) Cells() int {
+	cells := 0
+	for _, present := range s.present {
+		if present {
+			cells++
+		}
+	}
+
+	return cells
+}
+
+// NewShapedGame returns the game on the board of the shape. Black holes are
+// placed on existing cells only.
+// If the number of black holes exceeds the number of cells or the topology is
+// a Grid not stored by the board of the shape, then NewShapedGame panics.
+func NewShapedGame(shape Shape, blackHolesNumber int, options ...Option) *Game {
+	o := newOptions(options)
+	if err := o.checkBoard(shape.rows, shape.columns); err != nil {
+		panic(err.Error())
+	}
+
+	board := createGameState(shape.rows, shape.columns, Cell{Content: ZeroCellValue, State: HiddenState})
+	candidates := make([]cellAddress, 0, shape.Cells())
+	for i := range board {
+		for j := range board[i] {
+			if !shape.Has(i, j) {
+				board[i][j].State = AbsentState
+				continue
+			}
+			candidates = append(candidates, cellAddress{row: i, column: j})
+		}
+	}
+
+	replaceCells(board, pickAddresses(o.rand(), candidates, blackHolesNumber), Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, o.topology)
+
+	return o.newGame(board)
+}
+
+// pickAddresses returns amount of random addresses out of candidates.
+func pickAddresses(rnd intner, candidates []cellAddress, amount int) []cellAddress {
+	if amount < 0 {
+		panic("amount must not be negative")
+	}
+	if amount > len(candidates) {
+		panic("number of black holes does not fit in game board")
+	}
+
+	// partial Fisher-Yates shuffle of the candidates
+	addresses := make([]cellAddress, len(candidates))
+	copy(addresses, candidates)
+	for i := 0; i < amount; i++ {
+		j := i + rnd.Intn(len(addresses)-i)
+		addresses[i], addresses[j] = addresses[j], addresses[i]
+	}
+
+	return addresses[:amount]
+}
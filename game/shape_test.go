@@ -0,0 +1,126 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShape(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     [][]bool
+		wantErr  bool
+	}{
+		{
+			name:     "hole",
+			template: "###\n# #\n###\n",
+			want: [][]bool{
+				{true, true, true},
+				{true, false, true},
+				{true, true, true},
+			},
+		},
+		{
+			name:     "short lines and trailing empty lines",
+			template: "#\r\n ##\n\n\n",
+			want: [][]bool{
+				{true, false, false},
+				{false, true, true},
+			},
+		},
+		{
+			name:     "no cells",
+			template: "   \n\n",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseShape(strings.NewReader(tt.template))
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, shapeCells(got))
+		})
+	}
+}
+
+func TestCircleShape(t *testing.T) {
+	assert.Equal(t, [][]bool{
+		{false, true, true, true, false},
+		{true, true, true, true, true},
+		{true, true, true, true, true},
+		{true, true, true, true, true},
+		{false, true, true, true, false},
+	}, shapeCells(CircleShape(5)))
+}
+
+func TestShape_Without(t *testing.T) {
+	full := NewShape(2, 2)
+	shape := full.Without(0, 1).Without(5, 5)
+
+	assert.Equal(t, [][]bool{{true, false}, {true, true}}, shapeCells(shape))
+	assert.Equal(t, 3, shape.Cells())
+	assert.Equal(t, 4, full.Cells())
+}
+
+func TestNewShapedGame(t *testing.T) {
+	shape := CircleShape(6)
+	game := NewShapedGame(shape, shape.Cells())
+
+	for i, row := range game.GetState() {
+		for j, cell := range row {
+			if shape.Has(i, j) {
+				assert.Equal(t, Cell{Content: BlackHoleCellValue, State: HiddenState}, cell)
+			} else {
+				assert.Equal(t, AbsentState, cell.State)
+			}
+		}
+	}
+	assert.Panics(t, func() { NewShapedGame(shape, shape.Cells()+1) })
+}
+
+func TestGame_RevealCell_shaped(t *testing.T) {
+	board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
+	replaceCells(board, []cellAddress{{row: 0, column: 1}, {row: 1, column: 1}, {row: 2, column: 1}}, Cell{Content: ZeroCellValue, State: AbsentState})
+	replaceCells(board, []cellAddress{{row: 0, column: 2}}, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, Square{})
+	game := &Game{failAt: nil, board: board}
+
+	game.RevealCell(2, 0)
+
+	// the absent column stops opening contiguous space
+	assert.Equal(t, [][]Cell{
+		{{Content: ZeroCellValue, State: VisibleState}, {Content: ZeroCellValue, State: AbsentState}, {Content: BlackHoleCellValue, State: HiddenState}},
+		{{Content: ZeroCellValue, State: VisibleState}, {Content: ZeroCellValue, State: AbsentState}, {Content: OneCellValue, State: HiddenState}},
+		{{Content: ZeroCellValue, State: VisibleState}, {Content: ZeroCellValue, State: AbsentState}, {Content: ZeroCellValue, State: HiddenState}},
+	}, game.GetState())
+	assert.False(t, game.Completed())
+
+	game.RevealCell(2, 2)
+	assert.True(t, game.Won())
+
+	game = &Game{board: createGameState(1, 2, Cell{State: AbsentState})}
+	game.board[0][1].State = HiddenState
+	assert.Panics(t, func() { game.RevealCell(0, 0) })
+}
+
+func shapeCells(s Shape) [][]bool {
+	cells := make([][]bool, s.Rows())
+	for i := range cells {
+		cells[i] = make([]bool, s.Columns())
+		for j := range cells[i] {
+			cells[i][j] = s.Has(i, j)
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,170 @@
+package game
+
+import "time"
+
+// Status is the phase of the game lifecycle.
+type Status int
+
+const (
+	// NotStarted is the status of games before the first move.
+	NotStarted Status = iota
+	// InProgress is the status of started games which are not completed.
+	InProgress
+	// Paused is the status of games paused by Pause. Moves can not be made
+	// and the timer is stopped until Resume.
+	Paused
+	// Won is the status of games with all cells except black holes visible.
+	Won
+	// Lost is the status of games with a black hole revealed.
+	Lost
+	// Resigned is the status of games given up by Resign.
+	Resigned
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case NotStarted:
+		return "NotStarted"
+	case InProgress:
+		return "InProgress"
+	case Paused:
+		return "Paused"
+	case Won:
+		return "Won"
+	case Lost:
+		return "Lost"
+	case Resigned:
+		return "Resigned"
+	default:
+		return "Unknown"
+	}
+}
+
+// transitions lists statuses each status may change to. Games start by the
+// first move or Resume, and are completed by moves or Resign.
+var transitions = map[Status][]Status{
+	NotStarted: {InProgress, Won, Lost, Resigned},
+	InProgress: {Paused, Won, Lost, Resigned},
+	Paused:     {InProgress, Resigned},
+}
+
+// CanTransition returns true if the game of the from status may change to the
+// to status. Otherwise false is returned. Won, Lost and Resigned games never
+// change their status.
+func CanTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithClock makes the game measure time by now. time.Now is used by default.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
+
+// Status returns the current status of the game.
+func (g *Game) Status() Status {
+	switch {
+	case g.failAt != nil:
+		return Lost
+	case g.status == Resigned:
+		return Resigned
+	case g.won():
+		return Won
+	default:
+		return g.status
+	}
+}
+
+// Elapsed returns the time the game has been in progress. The timer starts
+// with the first move, and stops while the game is paused and once it is
+// completed.
+func (g *Game) Elapsed() time.Duration {
+	if g.status == InProgress && !g.Completed() {
+		return g.elapsed + g.now().Sub(g.resumedAt)
+	}
+
+	return g.elapsed
+}
+
+// Pause pauses the game in progress. Moves can not be made until Resume.
+// If the game is not in progress, then Pause panics.
+func (g *Game) Pause() {
+	g.transition(g.Status(), Paused)
+	g.emit(GamePaused{})
+}
+
+// Resume resumes the paused game. The game which is not started is started,
+// so its timer runs before the first move.
+// If the game is in progress or completed, then Resume panics.
+func (g *Game) Resume() {
+	g.transition(g.Status(), InProgress)
+	g.emit(GameResumed{})
+}
+
+// Resign gives up the game, which completes it. Games may be resigned before
+// the first move to abandon them.
+// If the game is completed, then Resign panics.
+func (g *Game) Resign() {
+	g.transition(g.Status(), Resigned)
+	g.emit(GameResigned{})
+}
+
+// startMove starts the game and its timer on the first move.
+// If the game is paused, then startMove panics.
+func (g *Game) startMove() {
+	switch g.status {
+	case Paused:
+		panic("game paused")
+	case NotStarted:
+		g.transition(NotStarted, InProgress)
+	}
+}
+
+// finishMove stores the status of the game completed by the move.
+func (g *Game) finishMove() {
+	if to := g.Status(); to == Won || to == Lost {
+		g.transition(g.status, to)
+	}
+}
+
+// transition changes the status of the game from the status to the status.
+// The timer runs only while the game is in progress. All status changes are
+// made by transition, so they follow transitions.
+// If CanTransition does not allow the change, then transition panics.
+func (g *Game) transition(from, to Status) {
+	if !CanTransition(from, to) {
+		switch {
+		case from == Won || from == Lost || from == Resigned:
+			panic("game over")
+		case to == InProgress:
+			panic("game is not paused")
+		default:
+			panic("game is not in progress")
+		}
+	}
+
+	now := g.now()
+	if from == InProgress {
+		g.elapsed += now.Sub(g.resumedAt)
+	}
+	if to == InProgress {
+		g.resumedAt = now
+	}
+	g.status = to
+}
+
+func (g *Game) now() time.Time {
+	if g.clock == nil {
+		return time.Now()
+	}
+
+	return g.clock()
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want bool
+	}{
+		{from: NotStarted, to: InProgress, want: true},
+		{from: NotStarted, to: Paused, want: false},
+		{from: NotStarted, to: Resigned, want: true},
+		{from: InProgress, to: Paused, want: true},
+		{from: InProgress, to: Won, want: true},
+		{from: InProgress, to: NotStarted, want: false},
+		{from: Paused, to: InProgress, want: true},
+		{from: Paused, to: Won, want: false},
+		{from: Paused, to: Resigned, want: true},
+		{from: InProgress, to: Lost, want: true},
+		{from: Paused, to: Lost, want: false},
+		{from: Won, to: InProgress, want: false},
+		{from: Lost, to: Resigned, want: false},
+		{from: Resigned, to: InProgress, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from.String()+" to "+tt.to.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+// fakeClock is the clock of tests moved by advance.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestGame_lifecycle(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	game := parseBoard(t, "*..\n...\n*..\n", WithClock(clock.Now))
+	var events []Event
+	game.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	assert.Equal(t, NotStarted, game.Status())
+	assert.Panics(t, game.Pause)
+	clock.advance(time.Minute)
+	assert.Equal(t, time.Duration(0), game.Elapsed())
+
+	game.ToggleFlag(0, 0)
+	assert.Equal(t, InProgress, game.Status())
+	clock.advance(10 * time.Second)
+	assert.Equal(t, 10*time.Second, game.Elapsed())
+
+	game.Pause()
+	assert.Equal(t, Paused, game.Status())
+	assert.Panics(t, game.Pause)
+	assert.PanicsWithValue(t, "game paused", func() { game.RevealCell(1, 1) })
+	assert.PanicsWithValue(t, "game paused", func() { game.ToggleFlag(2, 0) })
+	clock.advance(time.Hour)
+	assert.Equal(t, 10*time.Second, game.Elapsed())
+
+	game.Resume()
+	assert.Panics(t, game.Resume)
+	clock.advance(5 * time.Second)
+	game.RevealCell(1, 1)
+	game.ToggleFlag(2, 0)
+	game.ChordCell(1, 1)
+	assert.Equal(t, Won, game.Status())
+	clock.advance(time.Minute)
+	assert.Equal(t, 15*time.Second, game.Elapsed())
+	assert.Panics(t, game.Resign)
+
+	assert.Contains(t, events, GamePaused{})
+	assert.Contains(t, events, GameResumed{})
+}
+
+func TestGame_Resume_notStarted(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	game := parseBoard(t, "*..\n...\n", WithClock(clock.Now))
+
+	game.Resume()
+	assert.Equal(t, InProgress, game.Status())
+	clock.advance(time.Minute)
+	assert.Equal(t, time.Minute, game.Elapsed())
+	assert.PanicsWithValue(t, "game is not paused", game.Resume)
+
+	game.RevealCell(1, 2)
+	game.RevealCell(1, 0)
+	assert.Equal(t, Won, game.Status())
+	assert.Equal(t, Won, game.status)
+	assert.PanicsWithValue(t, "game over", game.Resume)
+	assert.PanicsWithValue(t, "game over", game.Pause)
+	assert.NoError(t, game.Validate())
+}
+
+func TestGame_Resign(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	game := parseBoard(t, "*..\n...\n*..\n", WithClock(clock.Now))
+	var events []Event
+	game.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	game.RevealCell(1, 1)
+	clock.advance(3 * time.Second)
+	game.Pause()
+	clock.advance(time.Minute)
+	game.Resign()
+
+	assert.Equal(t, Resigned, game.Status())
+	assert.True(t, game.Completed())
+	assert.False(t, game.Won())
+	assert.False(t, game.Lost())
+	assert.Equal(t, 3*time.Second, game.Elapsed())
+	assert.Equal(t, GameResigned{}, events[len(events)-1])
+	assert.Panics(t, func() { game.RevealCell(0, 1) })
+	assert.Panics(t, game.Resign)
+
+	// games may be abandoned before the first move
+	game = parseBoard(t, "*..\n")
+	game.Resign()
+	assert.Equal(t, Resigned, game.Status())
+	assert.Equal(t, time.Duration(0), game.Elapsed())
+}
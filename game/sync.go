@@ -0,0 +1,68 @@
+package game
+
+import "sync"
+
+// SyncGame guards a Game for concurrent use. Mutations are serialized while
+// readers may inspect the game concurrently.
+type SyncGame struct {
+	mu   sync.RWMutex
+	game *Game
+}
+
+// NewSyncGame returns SyncGame guarding g. The game must not be accessed
+// other than through the returned SyncGame afterwards.
+func NewSyncGame(g *Game) *SyncGame {
+	return &SyncGame{game: g}
+}
+
+// Read calls fn with the game locked for reading. Other readers may run
+// concurrently, so fn must not modify the game or retain it after returning.
+func (s *SyncGame) Read(fn func(g *Game)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn(s.game)
+}
+
+// Update calls fn with the game locked for writing. Checks and moves made by
+// fn are applied atomically. fn must not retain the game after returning.
+func (s *SyncGame) Update(fn func(g *Game)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s.game)
+}
+
+// GetState clones the current Game state.
+func (s *SyncGame) GetState() [][]Cell {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.game.GetState()
+}
+
+// PlayerView returns the current Game state as seen by the player.
+func (s *SyncGame) PlayerView() [][]PlayerCell {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.game.PlayerView()
+}
+
+// PlayerBoard returns the current Game state as seen by the player in a single
+// allocation.
+func (s *SyncGame) PlayerBoard() Board {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.game.PlayerBoard()
+}
+
+// ChangesSince returns cells changed after the version as seen by the player
+// and the current version.
+func (s *SyncGame) ChangesSince(version uint64) (changes []CellChange, current uint64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.game.ChangesSince(version)
+}
@@ -0,0 +1,94 @@
+package game
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncGame_concurrentAccess(t *testing.T) {
+	const rows, columns = 20, 20
+
+	board := createGameState(rows, columns, Cell{Content: ZeroCellValue, State: HiddenState})
+	replaceCells(board, generateBlackHoleAddresses(globalRand{}, rows, columns, 60), Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, Square{})
+	sg := NewSyncGame(&Game{failAt: nil, board: board})
+
+	addresses := make([]cellAddress, 0, rows*columns)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < columns; j++ {
+			addresses = append(addresses, cellAddress{row: i, column: j})
+		}
+	}
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			shuffled := make([]cellAddress, len(addresses))
+			copy(shuffled, addresses)
+			rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+				shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+			})
+
+			for _, a := range shuffled {
+				sg.Update(func(g *Game) {
+					if g.Completed() {
+						return
+					}
+
+					cell := getCell(g.board, a)
+					switch {
+					case cell.Content == BlackHoleCellValue:
+						g.ToggleFlag(a.row, a.column)
+					case cell.State == HiddenState:
+						g.RevealCell(a.row, a.column)
+					}
+				})
+			}
+		}(int64(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			visible := 0
+			for n := 0; n < 100; n++ {
+				current := 0
+				for _, row := range sg.GetState() {
+					for _, cell := range row {
+						if cell.State == VisibleState {
+							current++
+						}
+					}
+				}
+
+				if current < visible {
+					t.Errorf("Visible cells decreased from %d to %d", visible, current)
+
+					return
+				}
+				visible = current
+
+				sg.Read(func(g *Game) {
+					if g.Lost() {
+						t.Errorf("Game lost without revealing black holes")
+					}
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	sg.Read(func(g *Game) {
+		assert.True(t, g.Won())
+	})
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Characters of the board text format. Each line is a row of the board and
+// each character is a cell.
+const (
+	// TextHidden is a hidden cell without black hole.
+	TextHidden = '.'
+	// TextBlackHole is a hidden black hole.
+	TextBlackHole = '*'
+	// TextFlagged is a flagged black hole.
+	TextFlagged = 'F'
+	// TextWronglyFlagged is a flagged cell without black hole.
+	TextWronglyFlagged = 'X'
+	// TextDetonated is the black hole revealed to lose the game.
+	TextDetonated = '@'
+	// TextAbsent is a cell missing from an irregular board.
+	TextAbsent = ' '
+	// TextLargeNumber is a visible cell showing a number above 9. Visible cells
+	// showing numbers up to 9 are written as digits.
+	TextLargeNumber = '+'
+)
+
+// ParseBoard reads the game from the board text format. Numbers of visible
+// cells must match black holes counted for the topology given by the options.
+// Games with visible or flagged cells are in progress, and their timer starts.
+// Short lines are padded with absent cells and trailing empty lines are
+// ignored. Boards not matching the Grid topology are rejected with errors
+// wrapping ErrTopologyMismatch.
+func ParseBoard(r io.Reader, options ...Option) (*Game, error) {
+	var lines [][]rune
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, []rune(strings.TrimRight(scanner.Text(), "\r")))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(string(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	columns := 0
+	for _, line := range lines {
+		if len(line) > columns {
+			columns = len(line)
+		}
+	}
+
+	o := newOptions(options)
+	if err := o.checkBoard(len(lines), columns); err != nil {
+		return nil, err
+	}
+	g := o.newGame(createGameState(len(lines), columns, Cell{Content: ZeroCellValue, State: AbsentState}))
+
+	// numbers of visible cells are checked once all black holes are placed
+	type shownCell struct {
+		address cellAddress
+		value   CellValue
+	}
+	var shown []shownCell
+
+	for i, line := range lines {
+		for j, r := range line {
+			cell := &g.board[i][j]
+
+			switch {
+			case r == TextAbsent:
+			case r == TextHidden:
+				*cell = Cell{Content: ZeroCellValue, State: HiddenState}
+			case r == TextBlackHole:
+				*cell = Cell{Content: BlackHoleCellValue, State: HiddenState}
+			case r == TextFlagged:
+				*cell = Cell{Content: BlackHoleCellValue, State: FlaggedState}
+			case r == TextWronglyFlagged:
+				*cell = Cell{Content: ZeroCellValue, State: FlaggedState}
+			case r == TextDetonated:
+				if g.failAt != nil {
+					return nil, fmt.Errorf("line %d column %d: second detonated black hole", i+1, j+1)
+				}
+				g.failAt = &cellAddress{row: i, column: j}
+				*cell = Cell{Content: BlackHoleCellValue, State: HiddenState}
+			case r == TextLargeNumber:
+				*cell = Cell{Content: ZeroCellValue, State: VisibleState}
+				shown = append(shown, shownCell{address: cellAddress{row: i, column: j}, value: UnknownCellValue})
+			case r >= '0' && r <= '9':
+				*cell = Cell{Content: ZeroCellValue, State: VisibleState}
+				shown = append(shown, shownCell{address: cellAddress{row: i, column: j}, value: CellValue(r - '0')})
+			default:
+				return nil, fmt.Errorf("line %d column %d: unexpected character %q", i+1, j+1, r)
+			}
+		}
+	}
+
+	updateNaboringBlackHolesCellValues(g.board, o.topology)
+
+	// boards with moves made are in progress from now on
+	for _, row := range g.board {
+		for _, cell := range row {
+			if cell.State == VisibleState || cell.State == FlaggedState {
+				g.startMove()
+			}
+		}
+	}
+	if g.failAt != nil {
+		g.startMove()
+	}
+
+	for _, c := range shown {
+		content := g.board[c.address.row][c.address.column].Content
+		if (c.value == UnknownCellValue && content <= 9) || (c.value != UnknownCellValue && c.value != content) {
+			return nil, fmt.Errorf("line %d column %d: cell neighbours %d black holes", c.address.row+1, c.address.column+1, content)
+		}
+	}
+
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// FormatBoard returns the game in the board text format. Trailing absent cells
+// of rows are omitted.
+func FormatBoard(g *Game) string {
+	text := strings.Builder{}
+	for i, row := range g.board {
+		line := make([]rune, len(row))
+		for j, cell := range row {
+			line[j] = formatCell(cell, g.failAt != nil && *g.failAt == cellAddress{row: i, column: j})
+		}
+
+		text.WriteString(strings.TrimRight(string(line), string(TextAbsent)))
+		text.WriteByte('\n')
+	}
+
+	return text.String()
+}
+
+func formatCell(c Cell, detonated bool) rune {
+	switch {
+	case detonated:
+		return TextDetonated
+	case c.State == AbsentState:
+		return TextAbsent
+	case c.State == FlaggedState && c.Content == BlackHoleCellValue:
+		return TextFlagged
+	case c.State == FlaggedState:
+		return TextWronglyFlagged
+	case c.Content == BlackHoleCellValue:
+		return TextBlackHole
+	case c.State == HiddenState:
+		return TextHidden
+	case c.Content > 9:
+		return TextLargeNumber
+	default:
+		return '0' + rune(c.Content)
+	}
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		options []Option
+		want    *Game
+		wantErr bool
+	}{
+		{
+			name: "all markers",
+			text: "*3X\n@3F\n 21\n",
+			want: &Game{
+				failAt: &cellAddress{row: 1, column: 0},
+				board: [][]Cell{
+					{{Content: BlackHoleCellValue, State: HiddenState}, {Content: ThreeCellValue, State: VisibleState}, {Content: OneCellValue, State: FlaggedState}},
+					{{Content: BlackHoleCellValue, State: HiddenState}, {Content: ThreeCellValue, State: VisibleState}, {Content: BlackHoleCellValue, State: FlaggedState}},
+					{{Content: ZeroCellValue, State: AbsentState}, {Content: TwoCellValue, State: VisibleState}, {Content: OneCellValue, State: VisibleState}},
+				},
+				topology: Square{},
+			},
+		},
+		{
+			name: "short lines and trailing empty lines",
+			text: "*\r\n.1\n\n",
+			want: &Game{
+				board: [][]Cell{
+					{{Content: BlackHoleCellValue, State: HiddenState}, {Content: ZeroCellValue, State: AbsentState}},
+					{{Content: OneCellValue, State: HiddenState}, {Content: OneCellValue, State: VisibleState}},
+				},
+				topology: Square{},
+			},
+		},
+		{
+			name:    "large number",
+			text:    "*****\n*****\n**+**\n*****\n*****\n",
+			options: []Option{WithTopology(RadiusMask(2))},
+			want: func() *Game {
+				board := createGameState(5, 5, Cell{Content: BlackHoleCellValue, State: HiddenState})
+				board[2][2] = Cell{Content: 24, State: VisibleState}
+
+				return &Game{board: board, topology: RadiusMask(2)}
+			}(),
+		},
+		{
+			name:    "torus",
+			text:    "*1.\n",
+			options: []Option{WithTopology(Torus{})},
+			want: &Game{
+				board:    [][]Cell{{{Content: BlackHoleCellValue}, {Content: OneCellValue, State: VisibleState}, {Content: OneCellValue}}},
+				topology: Torus{},
+			},
+		},
+		{
+			name:    "wrong number",
+			text:    "*2\n",
+			wantErr: true,
+		},
+		{
+			name:    "small large number",
+			text:    "*+\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown character",
+			text:    "*?\n",
+			wantErr: true,
+		},
+		{
+			name:    "second detonated black hole",
+			text:    "@@\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBoard(strings.NewReader(tt.text), tt.options...)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.board, got.board)
+			assert.Equal(t, tt.want.failAt, got.failAt)
+			assert.Equal(t, tt.want.topology, got.topology)
+		})
+	}
+}
+
+func TestParseBoard_status(t *testing.T) {
+	assert.Equal(t, NotStarted, parseBoard(t, "*..\n").Status())
+	assert.Equal(t, InProgress, parseBoard(t, "*1.\n").Status())
+	assert.Equal(t, InProgress, parseBoard(t, "F..\n").Status())
+	assert.Equal(t, Lost, parseBoard(t, "@..\n").Status())
+	assert.Equal(t, Won, parseBoard(t, "*10\n").Status())
+}
+
+func TestFormatBoard(t *testing.T) {
+	texts := []string{
+		"*3X\n@3F\n 21\n",
+		"..*\n. .\n\n. 1*\n",
+		"",
+	}
+	for _, text := range texts {
+		g, err := ParseBoard(strings.NewReader(text))
+		require.NoError(t, err)
+
+		assert.Equal(t, text, FormatBoard(g))
+	}
+}
+
+// parseBoard returns the game of the board text for tests.
+func parseBoard(t *testing.T, text string, options ...Option) *Game {
+	t.Helper()
+
+	g, err := ParseBoard(strings.NewReader(text), options...)
+	require.NoError(t, err)
+
+	return g
+}
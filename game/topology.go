@@ -0,0 +1,393 @@
+package game
+
+// Topology defines which cells of the board neighbour each other. Numbers of
+// cells count black holes among neighbours, and revealing an empty cell opens
+// its neighbours.
+type Topology interface {
+	// MaxNeighbours returns the maximum number of neighbours a cell may
+	// have, which is the maximum number a cell may show.
+	MaxNeighbours() int
+
+	// neighbours returns addresses of existing cells neighbouring the cell
+	// at a. Every neighbour is listed once and the cell itself is excluded.
+	neighbours(board [][]Cell, a cellAddress) []cellAddress
+}
+
+// Square is the classic topology where each cell neighbours up to eight
+// surrounding cells. Cells at edges and corners have fewer neighbours.
+type Square struct{}
+
+// MaxNeighbours returns 8.
+func (Square) MaxNeighbours() int {
+	return 8
+}
+
+func (Square) neighbours(board [][]Cell, a cellAddress) []cellAddress {
+	if a.row < 0 {
+		panic("row must not be negative")
+	}
+	if a.column < 0 {
+		panic("column must not be negative")
+	}
+
+	addresses := make([]cellAddress, 0, 8)
+
+	for i := a.row - 1; i <= a.row+1; i++ {
+		for j := a.column - 1; j <= a.column+1; j++ {
+			if i == a.row && j == a.column {
+				continue
+			}
+
+			address := cellAddress{
+				row:    i,
+				column: j,
+			}
+
+			if getCell(board, address) == nil {
+				continue
+			}
+
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// Torus is the topology where edges of the board wrap around, so every cell
+// neighbours eight surrounding cells. Cells of the first and the last rows
+// neighbour each other, so do cells of the first and the last columns.
+type Torus struct{}
+
+// MaxNeighbours returns 8.
+func (Torus) MaxNeighbours() int {
+	return 8
+}
+
+func (Torus) neighbours(board [][]Cell, a cellAddress) []cellAddress {
+	if getCell(board, a) == nil {
+		panic("non-existing cell addressed")
+	}
+
+	rows := len(board)
+	columns := len(board[a.row])
+	addresses := make([]cellAddress, 0, 8)
+
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			address := cellAddress{
+				row:    wrap(a.row+i, rows),
+				column: wrap(a.column+j, columns),
+			}
+
+			// narrow boards wrap onto the cell itself or the same neighbours
+			if address == a || addressInList(address, addresses) {
+				continue
+			}
+
+			if getCell(board, address) == nil {
+				continue
+			}
+
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// wrap returns i wrapped into [0, n).
+func wrap(i, n int) int {
+	return ((i % n) + n) % n
+}
+
+// Hex is the topology of hexagonal cells where each cell neighbours up to six
+// surrounding cells. Cells are addressed by offset coordinates where odd rows
+// are shifted right by half a cell, so a cell of an even row neighbours cells
+// of the same and the previous columns in adjacent rows, and a cell of an odd
+// row neighbours cells of the same and the next columns in adjacent rows.
+type Hex struct{}
+
+// MaxNeighbours returns 6.
+func (Hex) MaxNeighbours() int {
+	return 6
+}
+
+func (Hex) neighbours(board [][]Cell, a cellAddress) []cellAddress {
+	if a.row < 0 {
+		panic("row must not be negative")
+	}
+	if a.column < 0 {
+		panic("column must not be negative")
+	}
+
+	// shift of the adjacent rows columns
+	shift := -1
+	if a.row%2 == 1 {
+		shift = 0
+	}
+
+	candidates := []cellAddress{
+		{row: a.row - 1, column: a.column + shift},
+		{row: a.row - 1, column: a.column + shift + 1},
+		{row: a.row, column: a.column - 1},
+		{row: a.row, column: a.column + 1},
+		{row: a.row + 1, column: a.column + shift},
+		{row: a.row + 1, column: a.column + shift + 1},
+	}
+
+	addresses := make([]cellAddress, 0, len(candidates))
+	for _, address := range candidates {
+		if getCell(board, address) == nil {
+			continue
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+// Offset is a position of a cell relative to another cell.
+type Offset struct {
+	Row    int
+	Column int
+}
+
+// Mask is the topology where each cell neighbours cells at the offsets of the
+// mask. Cells at edges and corners have fewer neighbours.
+type Mask struct {
+	offsets []Offset
+}
+
+// NewMask returns Mask of the offsets. The zero offset and duplicates are
+// ignored.
+func NewMask(offsets ...Offset) Mask {
+	m := Mask{offsets: make([]Offset, 0, len(offsets))}
+	for _, o := range offsets {
+		if o == (Offset{}) || offsetInList(o, m.offsets) {
+			continue
+		}
+		m.offsets = append(m.offsets, o)
+	}
+
+	return m
+}
+
+// KnightMask returns Mask where each cell neighbours cells a chess knight's
+// move away.
+func KnightMask() Mask {
+	return NewMask(
+		Offset{Row: -2, Column: -1}, Offset{Row: -2, Column: 1},
+		Offset{Row: -1, Column: -2}, Offset{Row: -1, Column: 2},
+		Offset{Row: 1, Column: -2}, Offset{Row: 1, Column: 2},
+		Offset{Row: 2, Column: -1}, Offset{Row: 2, Column: 1},
+	)
+}
+
+// RadiusMask returns Mask where each cell neighbours cells at most radius rows
+// and columns away, for example radius 2 makes a 5x5 neighbourhood.
+// If radius is negative, then RadiusMask panics.
+func RadiusMask(radius int) Mask {
+	if radius < 0 {
+		panic("radius must not be negative")
+	}
+
+	offsets := make([]Offset, 0, (2*radius+1)*(2*radius+1))
+	for i := -radius; i <= radius; i++ {
+		for j := -radius; j <= radius; j++ {
+			offsets = append(offsets, Offset{Row: i, Column: j})
+		}
+	}
+
+	return NewMask(offsets...)
+}
+
+// Offsets returns offsets of the mask.
+func (m Mask) Offsets() []Offset {
+	offsets := make([]Offset, len(m.offsets))
+	copy(offsets, m.offsets)
+
+	return offsets
+}
+
+// MaxNeighbours returns the number of the mask offsets.
+func (m Mask) MaxNeighbours() int {
+	return len(m.offsets)
+}
+
+func (m Mask) neighbours(board [][]Cell, a cellAddress) []cellAddress {
+	addresses := make([]cellAddress, 0, len(m.offsets))
+	for _, o := range m.offsets {
+		address := cellAddress{
+			row:    a.row + o.Row,
+			column: a.column + o.Column,
+		}
+
+		if getCell(board, address) == nil {
+			continue
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+func offsetInList(offset Offset, list []Offset) bool {
+	for _, listed := range list {
+		if listed == offset {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Grid is the topology of N-dimensional boards where each cell neighbours up
+// to 3^N-1 surrounding cells, for example 26 cells of a three-dimensional
+// board. Cells of a board are stored row by row: the last dimension is the
+// column, and the rest of dimensions are flattened into the row.
+type Grid struct {
+	dims []int
+}
+
+// NewGrid returns Grid of the dimensions, from the outermost to the column.
+// For example NewGrid(layers, rows, columns) makes a three-dimensional grid.
+// If no dimensions are given or a dimension is not positive, then NewGrid
+// panics.
+func NewGrid(dims ...int) Grid {
+	if len(dims) == 0 {
+		panic("grid must have dimensions")
+	}
+	for _, d := range dims {
+		if d < 1 {
+			panic("grid dimensions must be positive")
+		}
+	}
+
+	g := Grid{dims: make([]int, len(dims))}
+	copy(g.dims, dims)
+
+	return g
+}
+
+// Dims returns dimensions of the grid.
+func (g Grid) Dims() []int {
+	dims := make([]int, len(g.dims))
+	copy(dims, g.dims)
+
+	return dims
+}
+
+// MaxNeighbours returns 3^N-1 for N dimensions of the grid.
+func (g Grid) MaxNeighbours() int {
+	n := 1
+	for range g.dims {
+		n *= 3
+	}
+
+	return n - 1
+}
+
+// Address returns the row and column of the board storing the cell at the
+// coordinates. If the number of coordinates does not match dimensions of the
+// grid or a coordinate is out of the grid, then Address panics.
+func (g Grid) Address(coordinates ...int) (row, column int) {
+	if len(coordinates) != len(g.dims) {
+		panic("coordinates do not match grid dimensions")
+	}
+
+	for k, c := range coordinates[:len(coordinates)-1] {
+		if c < 0 || c >= g.dims[k] {
+			panic("coordinates out of grid")
+		}
+		row = row*g.dims[k] + c
+	}
+
+	column = coordinates[len(coordinates)-1]
+	if column < 0 || column >= g.dims[len(g.dims)-1] {
+		panic("coordinates out of grid")
+	}
+
+	return row, column
+}
+
+// Coordinates returns coordinates of the cell stored at i row and j column of
+// the board. It is the inverse of Address.
+func (g Grid) Coordinates(i, j int) []int {
+	coordinates := make([]int, len(g.dims))
+	if len(g.dims) == 0 {
+		return coordinates
+	}
+
+	coordinates[len(g.dims)-1] = j
+	for k := len(g.dims) - 2; k >= 0; k-- {
+		coordinates[k] = i % g.dims[k]
+		i /= g.dims[k]
+	}
+
+	return coordinates
+}
+
+// size returns the number of rows and columns of the board storing the grid.
+func (g Grid) size() (rows, columns int) {
+	rows = 1
+	for _, d := range g.dims[:len(g.dims)-1] {
+		rows *= d
+	}
+
+	return rows, g.dims[len(g.dims)-1]
+}
+
+func (g Grid) neighbours(board [][]Cell, a cellAddress) []cellAddress {
+	if getCell(board, a) == nil {
+		panic("non-existing cell addressed")
+	}
+
+	origin := g.Coordinates(a.row, a.column)
+	addresses := make([]cellAddress, 0, g.MaxNeighbours())
+
+	// offsets run through {-1, 0, 1}^N like an odometer
+	offsets := make([]int, len(g.dims))
+	for k := range offsets {
+		offsets[k] = -1
+	}
+	coordinates := make([]int, len(g.dims))
+
+	for {
+		self, inside := true, true
+		for k, o := range offsets {
+			coordinates[k] = origin[k] + o
+			if o != 0 {
+				self = false
+			}
+			if coordinates[k] < 0 || coordinates[k] >= g.dims[k] {
+				inside = false
+			}
+		}
+
+		if !self && inside {
+			row, column := g.Address(coordinates...)
+			address := cellAddress{row: row, column: column}
+			if getCell(board, address) != nil {
+				addresses = append(addresses, address)
+			}
+		}
+
+		k := len(offsets) - 1
+		for ; k >= 0; k-- {
+			if offsets[k] < 1 {
+				offsets[k]++
+
+				break
+			}
+			offsets[k] = -1
+		}
+		if k < 0 {
+			break
+		}
+	}
+
+	return addresses
+}
@@ -0,0 +1,350 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopology_neighbours(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology Topology
+		rows     int
+		columns  int
+		address  cellAddress
+		want     []cellAddress
+	}{
+		{
+			name:     "square corner",
+			topology: Square{},
+			rows:     3,
+			columns:  3,
+			address:  cellAddress{row: 0, column: 0},
+			want:     []cellAddress{{row: 0, column: 1}, {row: 1, column: 0}, {row: 1, column: 1}},
+		},
+		{
+			name:     "square center",
+			topology: Square{},
+			rows:     3,
+			columns:  3,
+			address:  cellAddress{row: 1, column: 1},
+			want: []cellAddress{
+				{row: 0, column: 0}, {row: 0, column: 1}, {row: 0, column: 2},
+				{row: 1, column: 0}, {row: 1, column: 2},
+				{row: 2, column: 0}, {row: 2, column: 1}, {row: 2, column: 2},
+			},
+		},
+		{
+			name:     "torus corner",
+			topology: Torus{},
+			rows:     4,
+			columns:  4,
+			address:  cellAddress{row: 0, column: 0},
+			want: []cellAddress{
+				{row: 3, column: 3}, {row: 3, column: 0}, {row: 3, column: 1},
+				{row: 0, column: 3}, {row: 0, column: 1},
+				{row: 1, column: 3}, {row: 1, column: 0}, {row: 1, column: 1},
+			},
+		},
+		{
+			name:     "torus narrower than neighbourhood",
+			topology: Torus{},
+			rows:     2,
+			columns:  2,
+			address:  cellAddress{row: 0, column: 0},
+			want:     []cellAddress{{row: 1, column: 1}, {row: 1, column: 0}, {row: 0, column: 1}},
+		},
+		{
+			name:     "hex even row",
+			topology: Hex{},
+			rows:     4,
+			columns:  4,
+			address:  cellAddress{row: 2, column: 1},
+			want: []cellAddress{
+				{row: 1, column: 0}, {row: 1, column: 1},
+				{row: 2, column: 0}, {row: 2, column: 2},
+				{row: 3, column: 0}, {row: 3, column: 1},
+			},
+		},
+		{
+			name:     "hex odd row",
+			topology: Hex{},
+			rows:     4,
+			columns:  4,
+			address:  cellAddress{row: 1, column: 1},
+			want: []cellAddress{
+				{row: 0, column: 1}, {row: 0, column: 2},
+				{row: 1, column: 0}, {row: 1, column: 2},
+				{row: 2, column: 1}, {row: 2, column: 2},
+			},
+		},
+		{
+			name:     "hex corner",
+			topology: Hex{},
+			rows:     4,
+			columns:  4,
+			address:  cellAddress{row: 0, column: 0},
+			want:     []cellAddress{{row: 0, column: 1}, {row: 1, column: 0}},
+		},
+		{
+			name:     "hex odd row last column",
+			topology: Hex{},
+			rows:     4,
+			columns:  4,
+			address:  cellAddress{row: 3, column: 3},
+			want:     []cellAddress{{row: 2, column: 3}, {row: 3, column: 2}},
+		},
+		{
+			name:     "knight mask corner",
+			topology: KnightMask(),
+			rows:     5,
+			columns:  5,
+			address:  cellAddress{row: 0, column: 0},
+			want:     []cellAddress{{row: 1, column: 2}, {row: 2, column: 1}},
+		},
+		{
+			name:     "custom mask",
+			topology: NewMask(Offset{Row: 0, Column: 2}, Offset{}, Offset{Row: 0, Column: -2}, Offset{Row: 0, Column: 2}),
+			rows:     1,
+			columns:  5,
+			address:  cellAddress{row: 0, column: 2},
+			want:     []cellAddress{{row: 0, column: 4}, {row: 0, column: 0}},
+		},
+		{
+			name:     "two-dimensional grid matches square",
+			topology: NewGrid(3, 3),
+			rows:     3,
+			columns:  3,
+			address:  cellAddress{row: 0, column: 0},
+			want:     []cellAddress{{row: 0, column: 1}, {row: 1, column: 0}, {row: 1, column: 1}},
+		},
+		{
+			name:     "three-dimensional grid corner",
+			topology: NewGrid(2, 2, 2),
+			rows:     4,
+			columns:  2,
+			address:  cellAddress{row: 0, column: 0},
+			want: []cellAddress{
+				{row: 0, column: 1}, {row: 1, column: 0}, {row: 1, column: 1},
+				{row: 2, column: 0}, {row: 2, column: 1}, {row: 3, column: 0}, {row: 3, column: 1},
+			},
+		},
+		{
+			name:     "torus of single cell",
+			topology: Torus{},
+			rows:     1,
+			columns:  1,
+			address:  cellAddress{row: 0, column: 0},
+			want:     []cellAddress{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := createGameState(tt.rows, tt.columns, Cell{})
+
+			got := tt.topology.neighbours(board, tt.address)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGame_RevealCell_torus(t *testing.T) {
+	board := createGameState(4, 4, Cell{Content: ZeroCellValue, State: HiddenState})
+	replaceCells(board, []cellAddress{{row: 1, column: 1}}, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, Torus{})
+	game := &Game{failAt: nil, board: board, topology: Torus{}}
+
+	game.RevealCell(3, 3)
+
+	assert.Equal(t, [][]Cell{
+		{
+			{Content: OneCellValue, State: VisibleState},
+			{Content: OneCellValue, State: VisibleState},
+			{Content: OneCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+		{
+			{Content: OneCellValue, State: VisibleState},
+			{Content: BlackHoleCellValue, State: HiddenState},
+			{Content: OneCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+		{
+			{Content: OneCellValue, State: VisibleState},
+			{Content: OneCellValue, State: VisibleState},
+			{Content: OneCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+		{
+			{Content: ZeroCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+			{Content: ZeroCellValue, State: VisibleState},
+		},
+	}, game.GetState())
+	assert.True(t, game.Won())
+}
+
+func TestGame_RevealCell_hex(t *testing.T) {
+	board := createGameState(3, 3, Cell{Content: ZeroCellValue, State: HiddenState})
+	replaceCells(board, []cellAddress{{row: 1, column: 1}}, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, Hex{})
+	game := &Game{failAt: nil, board: board, topology: Hex{}}
+
+	game.RevealCell(0, 0)
+
+	// (0, 0) and (2, 0) do not neighbour the black hole in hex layout:
+	//  0 1 1
+	//   1 * 1
+	//  0 1 1
+	assert.Equal(t, [][]Cell{
+		{
+			{Content: ZeroCellValue, State: VisibleState},
+			{Content: OneCellValue, State: VisibleState},
+			{Content: OneCellValue, State: HiddenState},
+		},
+		{
+			{Content: OneCellValue, State: VisibleState},
+			{Content: BlackHoleCellValue, State: HiddenState},
+			{Content: OneCellValue, State: HiddenState},
+		},
+		{
+			{Content: ZeroCellValue, State: HiddenState},
+			{Content: OneCellValue, State: HiddenState},
+			{Content: OneCellValue, State: HiddenState},
+		},
+	}, game.GetState())
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, 8, KnightMask().MaxNeighbours())
+	assert.Equal(t, 0, RadiusMask(0).MaxNeighbours())
+	assert.Equal(t, 8, RadiusMask(1).MaxNeighbours())
+	assert.Equal(t, 24, RadiusMask(2).MaxNeighbours())
+	assert.Equal(t, []Offset{{Row: 1, Column: 1}}, NewMask(Offset{Row: 1, Column: 1}, Offset{Row: 1, Column: 1}).Offsets())
+	assert.Panics(t, func() { RadiusMask(-1) })
+}
+
+func Test_calculateNaboringBlackHoles_mask(t *testing.T) {
+	board := createGameState(5, 5, Cell{Content: BlackHoleCellValue})
+	board[2][2] = Cell{Content: ZeroCellValue}
+
+	assert.Equal(t, CellValue(24), calculateNaboringBlackHoles(board, RadiusMask(2), cellAddress{row: 2, column: 2}))
+	assert.Equal(t, CellValue(8), calculateNaboringBlackHoles(board, KnightMask(), cellAddress{row: 2, column: 2}))
+	assert.Equal(t, CellValue(2), calculateNaboringBlackHoles(board, KnightMask(), cellAddress{row: 0, column: 0}))
+}
+
+func TestNewGame_WithTopology(t *testing.T) {
+	game := NewGame(5, 25, WithTopology(Torus{}))
+	assert.Equal(t, Torus{}, game.Topology())
+
+	game = NewGame(5, 3)
+	assert.Equal(t, Square{}, game.Topology())
+}
+
+func TestGrid(t *testing.T) {
+	grid := NewGrid(3, 4, 5)
+
+	assert.Equal(t, []int{3, 4, 5}, grid.Dims())
+	assert.Equal(t, 26, grid.MaxNeighbours())
+	assert.Equal(t, 80, NewGrid(3, 3, 3, 3).MaxNeighbours())
+
+	row, column := grid.Address(2, 1, 3)
+	assert.Equal(t, 9, row)
+	assert.Equal(t, 3, column)
+	assert.Equal(t, []int{2, 1, 3}, grid.Coordinates(row, column))
+
+	board := createGameState(12, 5, Cell{})
+	center, _ := grid.Address(1, 1, 1)
+	assert.Len(t, grid.neighbours(board, cellAddress{row: center, column: 1}), 26)
+
+	assert.Panics(t, func() { NewGrid() })
+	assert.Panics(t, func() { NewGrid(3, 0) })
+	assert.Panics(t, func() { grid.Address(1, 1) })
+	assert.Panics(t, func() { grid.Address(3, 0, 0) })
+}
+
+func TestGame_RevealCell_grid(t *testing.T) {
+	grid := NewGrid(3, 2, 2)
+	board := createGameState(6, 2, Cell{Content: ZeroCellValue, State: HiddenState})
+	row, column := grid.Address(2, 1, 1)
+	replaceCells(board, []cellAddress{{row: row, column: column}}, Cell{Content: BlackHoleCellValue, State: HiddenState})
+	updateNaboringBlackHolesCellValues(board, grid)
+	game := &Game{failAt: nil, board: board, topology: grid}
+
+	row, column = grid.Address(0, 0, 0)
+	game.RevealCell(row, column)
+
+	// the empty first layer opens the second layer, which neighbours the
+	// black hole in the third layer
+	assert.Equal(t, [][]Cell{
+		{{Content: ZeroCellValue, State: VisibleState}, {Content: ZeroCellValue, State: VisibleState}},
+		{{Content: ZeroCellValue, State: VisibleState}, {Content: ZeroCellValue, State: VisibleState}},
+		{{Content: OneCellValue, State: VisibleState}, {Content: OneCellValue, State: VisibleState}},
+		{{Content: OneCellValue, State: VisibleState}, {Content: OneCellValue, State: VisibleState}},
+		{{Content: OneCellValue, State: HiddenState}, {Content: OneCellValue, State: HiddenState}},
+		{{Content: OneCellValue, State: HiddenState}, {Content: BlackHoleCellValue, State: HiddenState}},
+	}, game.GetState())
+	assert.False(t, game.Completed())
+}
+
+func TestNewGridGame(t *testing.T) {
+	game := NewGridGame(NewGrid(3, 4, 5), 10)
+
+	state := game.GetState()
+	assert.Len(t, state, 12)
+	assert.Len(t, state[0], 5)
+
+	blackHoles := 0
+	for _, row := range state {
+		for _, cell := range row {
+			if cell.Content == BlackHoleCellValue {
+				blackHoles++
+			}
+		}
+	}
+	assert.Equal(t, 10, blackHoles)
+	assert.Panics(t, func() { NewGridGame(Grid{}, 0) })
+}
+
+func TestNewGridGame_seed(t *testing.T) {
+	grid := NewGrid(3, 4, 5)
+	game := NewGridGame(grid, 10, WithSeed(42), WithTopology(Torus{}))
+
+	assert.Equal(t, grid, game.Topology())
+	assert.Equal(t, FormatBoard(game), FormatBoard(NewGridGame(grid, 10, WithSeed(42))))
+	assert.NotEqual(t, FormatBoard(game), FormatBoard(NewGridGame(grid, 10, WithSeed(43))))
+
+	seed, ok := game.Seed()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(42), seed)
+}
+
+func TestGrid_absentCells(t *testing.T) {
+	game := parseBoard(t, "*. \n...\n...\n", WithTopology(NewGrid(3, 3)))
+	assert.Equal(t, OneCellValue, game.GetState()[1][1].Content)
+	assert.Equal(t, ZeroCellValue, game.GetState()[1][2].Content)
+
+	shaped := NewShapedGame(CircleShape(5), 3, WithTopology(NewGrid(5, 5)))
+	assert.Equal(t, AbsentState, shaped.GetState()[0][0].State)
+	assert.NoError(t, shaped.Validate())
+}
+
+func TestGrid_boardMismatch(t *testing.T) {
+	grid := WithTopology(NewGrid(3, 3))
+
+	_, err := NewGameFromLayout(2, 2, nil, grid)
+	assert.ErrorIs(t, err, ErrTopologyMismatch)
+	_, err = ParseBoard(strings.NewReader("...\n...\n"), grid)
+	assert.ErrorIs(t, err, ErrTopologyMismatch)
+	_, err = NewGameFromLayout(3, 3, nil, WithTopology(Grid{}))
+	assert.ErrorIs(t, err, ErrTopologyMismatch)
+	assert.Panics(t, func() { NewGame(4, 1, grid) })
+	assert.Panics(t, func() { NewShapedGame(CircleShape(4), 1, grid) })
+
+	_, err = NewGameFromLayout(3, 3, nil, grid)
+	assert.NoError(t, err)
+}
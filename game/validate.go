@@ -0,0 +1,121 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks that the game is consistent: rows are of the same length,
+// numbers match black holes neighbouring cells, empty visible cells have their
+// neighbours opened, black holes are never visible, the failed cell is a hidden
+// black hole and the status agrees with the cells. Every violation found is
+// joined into the returned error. Consistent games return nil.
+func (g *Game) Validate() error {
+	var violations []error
+
+	columns := 0
+	if len(g.board) > 0 {
+		columns = len(g.board[0])
+	}
+	for i, row := range g.board {
+		if len(row) != columns {
+			violations = append(violations, fmt.Errorf("row %d has %d columns, want %d", i, len(row), columns))
+		}
+	}
+	if len(violations) > 0 {
+		// cells can not be addressed on ragged boards
+		return errors.Join(violations...)
+	}
+
+	moved := false
+	for i, row := range g.board {
+		for j, cell := range row {
+			a := cellAddress{row: i, column: j}
+			violations = append(violations, g.validateCell(a, cell)...)
+
+			if cell.State == VisibleState || cell.State == FlaggedState {
+				moved = true
+			}
+		}
+	}
+
+	if g.failAt != nil {
+		cell := getCell(g.board, *g.failAt)
+		switch {
+		case cell == nil:
+			violations = append(violations, fmt.Errorf("failed cell %d:%d is out of the board", g.failAt.row, g.failAt.column))
+		case cell.Content != BlackHoleCellValue:
+			violations = append(violations, fmt.Errorf("failed cell %d:%d is not a black hole", g.failAt.row, g.failAt.column))
+		case cell.State != HiddenState:
+			violations = append(violations, fmt.Errorf("failed cell %d:%d is not hidden", g.failAt.row, g.failAt.column))
+		}
+
+		if g.won() {
+			violations = append(violations, errors.New("game is lost with all safe cells visible"))
+		}
+		if g.status == Resigned {
+			violations = append(violations, errors.New("game is lost and resigned"))
+		}
+	}
+
+	switch g.status {
+	case NotStarted:
+		if moved || g.failAt != nil {
+			violations = append(violations, errors.New("game is not started, but moves are made"))
+		}
+	case InProgress, Paused, Resigned:
+	case Won:
+		if !g.won() || g.failAt != nil {
+			violations = append(violations, errors.New("game is stored won, but it is not"))
+		}
+	case Lost:
+		if g.failAt == nil {
+			violations = append(violations, errors.New("game is stored lost without the failed cell"))
+		}
+	default:
+		violations = append(violations, fmt.Errorf("unexpected stored status %s", g.status))
+	}
+
+	return errors.Join(violations...)
+}
+
+// validateCell returns violations of the cell at the address.
+func (g *Game) validateCell(a cellAddress, cell Cell) []error {
+	var violations []error
+
+	switch cell.State {
+	case AbsentState:
+		if cell.Content != ZeroCellValue {
+			violations = append(violations, fmt.Errorf("absent cell %d:%d holds %d", a.row, a.column, cell.Content))
+		}
+
+		return violations
+	case HiddenState, FlaggedState, VisibleState:
+	default:
+		violations = append(violations, fmt.Errorf("cell %d:%d has unknown state %d", a.row, a.column, cell.State))
+	}
+
+	if cell.Content == BlackHoleCellValue {
+		if cell.State == VisibleState {
+			violations = append(violations, fmt.Errorf("black hole %d:%d is visible", a.row, a.column))
+		}
+
+		return violations
+	}
+
+	if want := calculateNaboringBlackHoles(g.board, g.Topology(), a); cell.Content != want {
+		violations = append(violations, fmt.Errorf("cell %d:%d holds %d, but neighbours %d black holes", a.row, a.column, cell.Content, want))
+	}
+
+	if cell.State == VisibleState && cell.Content == ZeroCellValue {
+		for _, n := range g.Topology().neighbours(g.board, a) {
+			if getCell(g.board, n).State == HiddenState {
+				violations = append(violations, fmt.Errorf("empty cell %d:%d is visible with hidden neighbours", a.row, a.column))
+
+				break
+			}
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_Validate(t *testing.T) {
+	hidden := func(v CellValue) Cell { return Cell{Content: v, State: HiddenState} }
+	visible := func(v CellValue) Cell { return Cell{Content: v, State: VisibleState} }
+	blackHole := Cell{Content: BlackHoleCellValue, State: HiddenState}
+
+	tests := []struct {
+		name string
+		game *Game
+		want []string
+	}{
+		{
+			name: "new game",
+			game: NewGame(8, 10),
+		},
+		{
+			name: "game in progress",
+			game: parseBoard(t, "*1..*\n.....\n.....\n"),
+		},
+		{
+			name: "lost game",
+			game: parseBoard(t, "@F.\n221\n000\n"),
+		},
+		{
+			name: "shaped game",
+			game: NewShapedGame(CircleShape(6), 4),
+		},
+		{
+			name: "ragged rows",
+			game: &Game{board: [][]Cell{{hidden(0), hidden(0)}, {hidden(0)}}},
+			want: []string{"row 1 has 1 columns, want 2"},
+		},
+		{
+			name: "wrong numbers",
+			game: &Game{board: [][]Cell{{blackHole, hidden(2), hidden(1)}}},
+			want: []string{"cell 0:1 holds 2, but neighbours 1 black holes", "cell 0:2 holds 1, but neighbours 0 black holes"},
+		},
+		{
+			name: "visible black hole",
+			game: &Game{board: [][]Cell{{{Content: BlackHoleCellValue, State: VisibleState}, visible(1)}}, status: InProgress},
+			want: []string{"black hole 0:0 is visible"},
+		},
+		{
+			name: "stopped cascade",
+			game: &Game{board: [][]Cell{{visible(0), hidden(0)}}, status: InProgress},
+			want: []string{"empty cell 0:0 is visible with hidden neighbours"},
+		},
+		{
+			name: "failed at safe cell",
+			game: &Game{board: [][]Cell{{blackHole, hidden(1)}}, failAt: &cellAddress{row: 0, column: 1}, status: InProgress},
+			want: []string{"failed cell 0:1 is not a black hole"},
+		},
+		{
+			name: "failed out of the board",
+			game: &Game{board: [][]Cell{{blackHole, hidden(1)}}, failAt: &cellAddress{row: 1, column: 0}, status: InProgress},
+			want: []string{"failed cell 1:0 is out of the board"},
+		},
+		{
+			name: "lost and won",
+			game: &Game{board: [][]Cell{{blackHole, visible(1)}}, failAt: &cellAddress{row: 0, column: 0}, status: Resigned},
+			want: []string{"game is lost with all safe cells visible", "game is lost and resigned"},
+		},
+		{
+			name: "moves of not started game",
+			game: &Game{board: [][]Cell{{blackHole, visible(1), hidden(0)}}},
+			want: []string{"game is not started, but moves are made"},
+		},
+		{
+			name: "stored won with hidden safe cells",
+			game: &Game{board: [][]Cell{{blackHole, hidden(1)}}, status: Won},
+			want: []string{"game is stored won, but it is not"},
+		},
+		{
+			name: "stored lost without failed cell",
+			game: &Game{board: [][]Cell{{blackHole, visible(1)}}, status: Lost},
+			want: []string{"game is stored lost without the failed cell"},
+		},
+		{
+			name: "unknown state",
+			game: &Game{board: [][]Cell{{{Content: ZeroCellValue, State: 7}}}},
+			want: []string{"cell 0:0 has unknown state 7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.game.Validate()
+
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.want, strings.Split(err.Error(), "\n"))
+		})
+	}
+}
+
+func TestParseBoard_invalid(t *testing.T) {
+	_, err := ParseBoard(strings.NewReader("*1..\n..0.\n"))
+
+	assert.EqualError(t, err, "empty cell 1:2 is visible with hidden neighbours")
+}
@@ -0,0 +1,53 @@
+package game
+
+// UnknownCellValue is the content of cells hidden from the player.
+const UnknownCellValue CellValue = -2
+
+// PlayerCell is a cell as seen by the player. Content is UnknownCellValue
+// unless the cell is visible.
+type PlayerCell struct {
+	Content CellValue
+	State   CellState
+}
+
+// PlayerView returns the current Game state as seen by the player. Contents of
+// hidden and flagged cells are not exposed, so the view is safe to share with
+// clients during play.
+func (g *Game) PlayerView() [][]PlayerCell {
+	total := 0
+	for _, boardRow := range g.board {
+		total += len(boardRow)
+	}
+
+	cells := make([]PlayerCell, total)
+	rows := make([][]PlayerCell, len(g.board))
+	for i, boardRow := range g.board {
+		row := cells[:len(boardRow):len(boardRow)]
+		cells = cells[len(boardRow):]
+		for j, cell := range boardRow {
+			row[j] = newPlayerCell(cell)
+		}
+		rows[i] = row
+	}
+
+	return rows
+}
+
+func newPlayerCell(c Cell) PlayerCell {
+	if c.State != VisibleState {
+		return PlayerCell{Content: UnknownCellValue, State: c.State}
+	}
+
+	return PlayerCell{Content: c.Content, State: c.State}
+}
+
+// PlayerCellAt returns the cell at i row and j column as seen by the player.
+// If the cell does not exist, then ok is false.
+func (g *Game) PlayerCellAt(i, j int) (c PlayerCell, ok bool) {
+	cell := getCell(g.board, cellAddress{row: i, column: j})
+	if cell == nil {
+		return PlayerCell{}, false
+	}
+
+	return newPlayerCell(*cell), true
+}
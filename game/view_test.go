@@ -0,0 +1,52 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGame_PlayerView(t *testing.T) {
+	game := &Game{
+		failAt: nil,
+		board: [][]Cell{
+			{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: VisibleState}},
+			{{Content: BlackHoleCellValue, State: FlaggedState}, {Content: TwoCellValue, State: FlaggedState}},
+			{{Content: OneCellValue, State: HiddenState}, {Content: ZeroCellValue, State: VisibleState}},
+		},
+	}
+
+	got := game.PlayerView()
+
+	assert.Equal(t, [][]PlayerCell{
+		{{Content: UnknownCellValue, State: HiddenState}, {Content: OneCellValue, State: VisibleState}},
+		{{Content: UnknownCellValue, State: FlaggedState}, {Content: UnknownCellValue, State: FlaggedState}},
+		{{Content: UnknownCellValue, State: HiddenState}, {Content: ZeroCellValue, State: VisibleState}},
+	}, got)
+
+	// the view is detached from the game
+	got[0][0].Content = BlackHoleCellValue
+	assert.Equal(t, UnknownCellValue, game.PlayerView()[0][0].Content)
+}
+
+func TestGame_PlayerCellAt(t *testing.T) {
+	game := &Game{
+		failAt: nil,
+		board: [][]Cell{
+			{{Content: BlackHoleCellValue, State: HiddenState}, {Content: OneCellValue, State: VisibleState}},
+		},
+	}
+
+	got, ok := game.PlayerCellAt(0, 0)
+	assert.True(t, ok)
+	assert.Equal(t, PlayerCell{Content: UnknownCellValue, State: HiddenState}, got)
+
+	got, ok = game.PlayerCellAt(0, 1)
+	assert.True(t, ok)
+	assert.Equal(t, PlayerCell{Content: OneCellValue, State: VisibleState}, got)
+
+	_, ok = game.PlayerCellAt(1, 0)
+	assert.False(t, ok)
+	_, ok = game.PlayerCellAt(0, -1)
+	assert.False(t, ok)
+}